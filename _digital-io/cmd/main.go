@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"net/http"
 	"os"
@@ -38,22 +39,13 @@ func runMCPServer() {
 	// Create HTTP client to connect to running HTTP server
 	httpClient := server.NewHTTPClient("http://localhost:8327")
 	
-	// Test connection to HTTP server with retries
-	maxRetries := 3
-	var err error
-	for i := 0; i < maxRetries; i++ {
-		_, err = httpClient.GetSystemStatus()
-		if err == nil {
-			break
+	// Test connection to HTTP server, retrying while it starts up
+	if err := httpClient.HealthCheck(); err != nil {
+		var responseErr *server.ServerResponseError
+		if errors.As(err, &responseErr) {
+			logger.Fatal("HTTP server at localhost:8327 is running but returned an error. Last error: %v", err)
 		}
-		logger.Warn("Failed to connect to HTTP server (attempt %d/%d): %v", i+1, maxRetries, err)
-		if i < maxRetries-1 {
-			time.Sleep(time.Duration(i+1) * time.Second)
-		}
-	}
-	
-	if err != nil {
-		logger.Fatal("Failed to connect to HTTP server at localhost:8327 after %d attempts. Please ensure the HTTP server is running first. Last error: %v", maxRetries, err)
+		logger.Fatal("Failed to connect to HTTP server at localhost:8327. Please ensure the HTTP server is running first. Last error: %v", err)
 	}
 	
 	logger.Info("Successfully connected to HTTP server at localhost:8327")
@@ -93,7 +85,9 @@ func runHTTPServer() {
 	}
 
 	// Create the I/O bank simulation
-	bank := iobank.NewIOBank()
+	bankConfig := iobank.DefaultConfig()
+	bankConfig.SafeMode = config.GetSafeMode()
+	bank := iobank.NewIOBankWithConfig(bankConfig)
 	
 	// Start the simulation (inputs will change over time)
 	bank.StartSimulation()
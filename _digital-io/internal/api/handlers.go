@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,21 +15,40 @@ import (
 // APIHandler handles HTTP requests for the I/O bank
 type APIHandler struct {
 	ioBank *iobank.IOBank
+	config config.HTTPConfig
 }
 
-// NewAPIHandler creates a new API handler
+// NewAPIHandler creates a new API handler configured from the environment
 func NewAPIHandler(bank *iobank.IOBank) *APIHandler {
+	return NewAPIHandlerWithConfig(bank, config.GetHTTPConfig())
+}
+
+// NewAPIHandlerWithConfig creates a new API handler with the given HTTP configuration
+func NewAPIHandlerWithConfig(bank *iobank.IOBank, cfg config.HTTPConfig) *APIHandler {
 	return &APIHandler{
 		ioBank: bank,
+		config: cfg,
 	}
 }
 
 // SetupRoutes configures the HTTP routes
 func (h *APIHandler) SetupRoutes() *mux.Router {
 	r := mux.NewRouter()
+	// Only install the access log when enabled so it costs nothing otherwise
+	if h.config.AccessLog.Enabled {
+		r.Use(accessLogMiddleware(h.config.AccessLog))
+	}
+	r.Use(corsMiddleware(h.config.CORS))
+	// Limit before authenticating so that a flood of bad requests is throttled too
+	if h.config.RateLimit.ReadRate > 0 || h.config.RateLimit.WriteRate > 0 {
+		r.Use(rateLimitMiddleware(h.config.RateLimit))
+	}
+	if h.config.APIToken != "" {
+		r.Use(authMiddleware(h.config.APIToken))
+	}
 
 	// REST endpoints
-	r.HandleFunc("/status", h.handleStatus).Methods("GET")
+	r.HandleFunc("/status", h.handleStatus).Methods("GET").Name(routeHealth)
 	r.HandleFunc("/reset", h.handleReset).Methods("POST")
 	r.HandleFunc("/digital/input/{pin}", h.handleGetDigitalInput).Methods("GET")
 	r.HandleFunc("/digital/output/{pin}", h.handleSetDigitalOutput).Methods("POST")
@@ -45,13 +65,16 @@ func (h *APIHandler) SetupRoutes() *mux.Router {
 	// MCP message recording endpoint
 	r.HandleFunc("/mcp/message", h.handleRecordMCPMessage).Methods("POST")
 
+	// CORS preflight requests for any path
+	r.PathPrefix("/").Methods(http.MethodOptions).HandlerFunc(handlePreflight)
+
 	// Serve static files for a simple web interface
 	webPath, err := config.GetWebPath()
 	if err != nil {
 		// Fallback to current working directory approach
 		webPath = "web"
 	}
-	r.PathPrefix("/").Handler(http.FileServer(http.Dir(webPath)))
+	r.PathPrefix("/").Handler(http.FileServer(http.Dir(webPath))).Name(routeStatic)
 
 	return r
 }
@@ -109,6 +132,10 @@ func (h *APIHandler) handleSetDigitalOutput(w http.ResponseWriter, r *http.Reque
 	}
 
 	err = h.ioBank.SetDigitalOutput(pin, value)
+	if errors.Is(err, iobank.ErrInterlock) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
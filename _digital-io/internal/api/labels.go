@@ -89,10 +89,27 @@ func (h *APIHandler) ReloadLabelsHandler(w http.ResponseWriter, r *http.Request)
 	})
 }
 
-// AddLabelsToStatus adds labels to the status response
+// StatusSchemaVersion is the version of the /status response.
+// It only changes when a field is removed or changes meaning, new fields may be added at any time
+const StatusSchemaVersion = 1
+
+// EngineeringReading is an analog pin reading converted to the unit of its configured range
+type EngineeringReading struct {
+	Pin     int     `json:"pin"`
+	Label   string  `json:"label"`
+	Raw     float64 `json:"raw"`
+	RawUnit string  `json:"raw_unit"`
+	Value   float64 `json:"value"`
+	Unit    string  `json:"unit"`
+}
+
+// AddLabelsToStatus adds the schema version, labels, analog ranges and the analog
+// readings converted to engineering units to the status response
 func AddLabelsToStatus(status map[string]interface{}) map[string]interface{} {
 	labels := config.GetIOLabels()
 	
+	status["schemaVersion"] = StatusSchemaVersion
+
 	// Add labels to the status
 	status["labels"] = map[string]interface{}{
 		"digital_inputs":  labels.DigitalInputs,
@@ -106,6 +123,55 @@ func AddLabelsToStatus(status map[string]interface{}) map[string]interface{} {
 		"inputs":  labels.AnalogInputRanges,
 		"outputs": labels.AnalogOutputRanges,
 	}
+
+	status["analog_inputs_engineering"] = engineeringReadings(status["analog_inputs"], labels.AnalogInputs, labels.AnalogInputRanges)
+	status["analog_outputs_engineering"] = engineeringReadings(status["analog_outputs"], labels.AnalogOutputs, labels.AnalogOutputRanges)
 	
 	return status
 }
+
+// engineeringReadings converts the raw voltages of a set of analog pins using their ranges.
+// Pins without a usable range are reported in volts
+func engineeringReadings(raw interface{}, pinLabels map[string]string, ranges map[string]config.AnalogRange) []EngineeringReading {
+	volts := analogVolts(raw)
+	readings := make([]EngineeringReading, 0, len(volts))
+	for pin, v := range volts {
+		key := strconv.Itoa(pin)
+		reading := EngineeringReading{
+			Pin:     pin,
+			Label:   pinLabels[key],
+			Raw:     v,
+			RawUnit: "V",
+			Value:   v,
+			Unit:    "V",
+		}
+		if r, ok := ranges[key]; ok {
+			if value, ok := r.EngineeringValue(v); ok {
+				reading.Value = value
+				reading.Unit = r.Unit
+			}
+		}
+		readings = append(readings, reading)
+	}
+	return readings
+}
+
+// analogVolts reads analog values from a status taken directly from the IO bank
+// or decoded from a JSON status response
+func analogVolts(raw interface{}) []float64 {
+	switch values := raw.(type) {
+	case [4]float64:
+		return values[:]
+	case []float64:
+		return values
+	case []interface{}:
+		ret := make([]float64, 0, len(values))
+		for _, v := range values {
+			f, _ := v.(float64)
+			ret = append(ret, f)
+		}
+		return ret
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/richard-senior/mcp/_digital-io/internal/config"
+	"github.com/richard-senior/mcp/_digital-io/internal/logger"
+)
+
+// RequestIDHeader is the header used to correlate a response with the access log
+const RequestIDHeader = "X-Request-ID"
+
+// maxLoggedBody is the largest request body that will be written to the access log
+const maxLoggedBody = 4096
+
+const redactedValue = "[REDACTED]"
+
+// Names of routes which don't require authentication
+const (
+	routeHealth = "health"
+	routeStatic = "static"
+)
+
+// corsMiddleware adds CORS headers for permitted origins and answers preflight requests
+func corsMiddleware(cors config.CORSConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" || !cors.IsOriginAllowed(origin) {
+				// Same-origin request or an origin we don't allow, browsers will block the latter
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+
+			// Preflight request
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", strings.Join(cors.AllowedMethods, ", "))
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(cors.AllowedHeaders, ", "))
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handlePreflight answers OPTIONS requests which are not from an allowed origin
+func handlePreflight(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// accessLogMiddleware logs each request with its status and duration, tagging responses with a request ID
+// Header and JSON body values named in the config are redacted
+func accessLogMiddleware(cfg config.AccessLogConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			// Read the body so it can be logged, then put it back for the handler
+			var body []byte
+			if r.Body != nil && r.ContentLength != 0 {
+				body, _ = io.ReadAll(io.LimitReader(r.Body, maxLoggedBody+1))
+				r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+			}
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			logger.Info(fmt.Sprintf("[%s] %s %s %d %s", requestID, r.Method, r.URL.Path, rec.status, time.Since(start)))
+			logger.Debug(fmt.Sprintf("[%s] headers: %s", requestID, redactHeaders(r.Header, cfg)))
+			if len(body) > 0 {
+				logger.Debug(fmt.Sprintf("[%s] body: %s", requestID, redactBody(body, cfg)))
+			}
+		})
+	}
+}
+
+// redactHeaders renders the request headers with sensitive values masked
+func redactHeaders(headers http.Header, cfg config.AccessLogConfig) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(headers[name], ",")
+		if cfg.IsRedacted(name) {
+			value = redactedValue
+		}
+		parts = append(parts, name+"="+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// redactBody renders a request body with sensitive JSON fields masked
+// Bodies which are not JSON or are too large are not logged verbatim
+func redactBody(body []byte, cfg config.AccessLogConfig) string {
+	if len(body) > maxLoggedBody {
+		return fmt.Sprintf("(%d+ bytes, not logged)", maxLoggedBody)
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Sprintf("(%d bytes, not JSON)", len(body))
+	}
+	redacted, err := json.Marshal(redactValue(decoded, cfg))
+	if err != nil {
+		return fmt.Sprintf("(%d bytes)", len(body))
+	}
+	return string(redacted)
+}
+
+// redactValue masks the values of sensitive fields at any depth in a decoded JSON value
+func redactValue(value any, cfg config.AccessLogConfig) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if cfg.IsRedacted(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(inner, cfg)
+			}
+		}
+		return v
+	case []any:
+		for i, inner := range v {
+			v[i] = redactValue(inner, cfg)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// newRequestID returns a random identifier for correlating logs with responses
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// authMiddleware rejects API requests which don't carry the configured bearer token
+// Health checks, CORS preflights and the static web interface are exempt
+func authMiddleware(token string) mux.MiddlewareFunc {
+	expected := []byte(token)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if route := mux.CurrentRoute(r); route != nil {
+				if name := route.GetName(); name == routeHealth || name == routeStatic {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(presented)), expected) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="digital-io"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/richard-senior/mcp/_digital-io/internal/config"
+	"github.com/richard-senior/mcp/_digital-io/internal/logger"
+)
+
+// maxIdleBuckets is how many buckets are kept before full (idle) ones are discarded
+const maxIdleBuckets = 1024
+
+// tokenBucket holds the tokens available to one client or route
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a set of token buckets sharing a refill rate and burst size
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// newRateLimiter creates a limiter allowing rate requests per second with bursts of up to burst
+// A burst below one defaults to the rate, rounded up
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	b := float64(burst)
+	if b < 1 {
+		b = math.Max(1, math.Ceil(rate))
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   b,
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the key's bucket, returning false and how long until
+// a token will be available if the bucket is empty
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok {
+		l.prune(now)
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// prune discards buckets which have refilled completely, as they're no different
+// from a new bucket, once there are too many of them
+func (l *rateLimiter) prune(now time.Time) {
+	if len(l.buckets) < maxIdleBuckets {
+		return
+	}
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitMiddleware rejects requests exceeding the configured rates with 429 Too Many Requests
+// and a Retry-After header. Reads and writes have separate limits so that polling the status
+// doesn't use up the allowance for setting outputs. Preflights and static files aren't limited
+func rateLimitMiddleware(cfg config.RateLimitConfig) mux.MiddlewareFunc {
+	var reads, writes *rateLimiter
+	if cfg.ReadRate > 0 {
+		reads = newRateLimiter(cfg.ReadRate, cfg.ReadBurst)
+	}
+	if cfg.WriteRate > 0 {
+		writes = newRateLimiter(cfg.WriteRate, cfg.WriteBurst)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limiter := reads
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				limiter = writes
+			}
+			route := mux.CurrentRoute(r)
+			if limiter == nil || r.Method == http.MethodOptions || (route != nil && route.GetName() == routeStatic) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := clientIP(r)
+			if cfg.PerRoute && route != nil {
+				if template, err := route.GetPathTemplate(); err == nil {
+					key = template
+				}
+			}
+			key = r.Method + " " + key
+
+			if ok, wait := limiter.allow(key); !ok {
+				seconds := int(math.Ceil(wait.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				logger.Warn("Rate limit exceeded for %s %s by %s", r.Method, r.URL.Path, clientIP(r))
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the address of the caller without its port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/richard-senior/mcp/_digital-io/internal/logger"
+)
+
+// Environment variables used to configure the HTTP API
+const (
+	EnvCORSOrigins = "DIGITAL_IO_CORS_ORIGINS"
+	EnvCORSMethods = "DIGITAL_IO_CORS_METHODS"
+	EnvCORSHeaders = "DIGITAL_IO_CORS_HEADERS"
+	EnvAccessLog   = "DIGITAL_IO_ACCESS_LOG"
+	EnvRedact      = "DIGITAL_IO_LOG_REDACT"
+	EnvAPIToken    = "DIGITAL_IO_API_TOKEN"
+
+	EnvRateLimitWrite      = "DIGITAL_IO_RATE_LIMIT_WRITE"
+	EnvRateLimitWriteBurst = "DIGITAL_IO_RATE_LIMIT_WRITE_BURST"
+	EnvRateLimitRead       = "DIGITAL_IO_RATE_LIMIT_READ"
+	EnvRateLimitReadBurst  = "DIGITAL_IO_RATE_LIMIT_READ_BURST"
+	EnvRateLimitKey        = "DIGITAL_IO_RATE_LIMIT_KEY"
+)
+
+// defaultRedactedFields are the headers and JSON body fields never written to the access log
+var defaultRedactedFields = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "api_key", "token", "password", "secret"}
+
+// CORSConfig defines which cross-origin callers may use the HTTP API
+// An empty AllowedOrigins list means same-origin only
+type CORSConfig struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// AccessLogConfig controls request/response logging
+// RedactedFields lists header names and JSON body fields whose values are masked
+type AccessLogConfig struct {
+	Enabled        bool
+	RedactedFields []string
+}
+
+// RateLimitConfig controls the token bucket rate limiting of API requests
+// Rates are requests per second and a rate of zero disables limiting of that kind of request.
+// Writes (POSTs) are limited separately, and usually more strictly, than reads
+// Buckets are kept per client IP unless PerRoute is set, when each route shares one bucket
+type RateLimitConfig struct {
+	WriteRate  float64
+	WriteBurst int
+	ReadRate   float64
+	ReadBurst  int
+	PerRoute   bool
+}
+
+// HTTPConfig holds the settings for the HTTP API server
+// APIToken, when set, must be presented as a bearer token on every API request
+type HTTPConfig struct {
+	CORS      CORSConfig
+	AccessLog AccessLogConfig
+	APIToken  string
+	RateLimit RateLimitConfig
+}
+
+// GetHTTPConfig builds the HTTP API configuration from the environment
+func GetHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		CORS: CORSConfig{
+			AllowedOrigins: splitEnvList(EnvCORSOrigins, nil),
+			AllowedMethods: splitEnvList(EnvCORSMethods, []string{"GET", "POST", "OPTIONS"}),
+			AllowedHeaders: splitEnvList(EnvCORSHeaders, []string{"Content-Type", "Authorization"}),
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:        envBool(EnvAccessLog),
+			RedactedFields: splitEnvList(EnvRedact, defaultRedactedFields),
+		},
+		APIToken: GetAPIToken(),
+		RateLimit: RateLimitConfig{
+			WriteRate:  envFloat(EnvRateLimitWrite, 10),
+			WriteBurst: int(envFloat(EnvRateLimitWriteBurst, 20)),
+			ReadRate:   envFloat(EnvRateLimitRead, 50),
+			ReadBurst:  int(envFloat(EnvRateLimitReadBurst, 100)),
+			PerRoute:   strings.EqualFold(strings.TrimSpace(os.Getenv(EnvRateLimitKey)), "route"),
+		},
+	}
+}
+
+// IsRedacted returns true if the named header or body field must not be logged
+func (c AccessLogConfig) IsRedacted(name string) bool {
+	for _, field := range c.RedactedFields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAPIToken returns the bearer token shared by the HTTP API and its clients, empty if auth is disabled
+func GetAPIToken() string {
+	return strings.TrimSpace(os.Getenv(EnvAPIToken))
+}
+
+// IsOriginAllowed returns true if the given origin may call the API cross-origin
+func (c CORSConfig) IsOriginAllowed(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// envBool reads a boolean flag from the environment
+func envBool(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(name))) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// envFloat reads a non-negative number from the environment
+func envFloat(name string, def float64) float64 {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		logger.Warn("Ignoring invalid value for %s: %s", name, value)
+		return def
+	}
+	return f
+}
+
+// splitEnvList reads a comma separated list from the environment
+func splitEnvList(name string, defaults []string) []string {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return defaults
+	}
+	var ret []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,11 @@
+package config
+
+// Environment variables used to configure the simulation
+const (
+	EnvSafeMode = "DIGITAL_IO_SAFE_MODE"
+)
+
+// GetSafeMode returns true if the IO bank's safety interlocks should be enforced
+func GetSafeMode() bool {
+	return envBool(EnvSafeMode)
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AnalogFullScaleVolts is the voltage at which an analog pin reads its range's MaxValue,
+// 0V reading MinValue. Ranges are linear between the two
+const AnalogFullScaleVolts = 5.0
+
+// EngineeringValue converts a raw pin voltage into the range's engineering unit,
+// e.g. 1V on a 0-100°C range is 20°C.
+// Returns false if the range's limits aren't numbers
+func (r AnalogRange) EngineeringValue(volts float64) (float64, bool) {
+	min, err := strconv.ParseFloat(strings.TrimSpace(r.MinValue), 64)
+	if err != nil {
+		return 0, false
+	}
+	max, err := strconv.ParseFloat(strings.TrimSpace(r.MaxValue), 64)
+	if err != nil {
+		return 0, false
+	}
+	return min + (volts/AnalogFullScaleVolts)*(max-min), true
+}
@@ -1,6 +1,7 @@
 package iobank
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -16,6 +17,35 @@ type MCPMessage struct {
 	Message   string    `json:"message"`
 }
 
+// DefaultTickInterval is how often the simulation updates the inputs unless configured otherwise
+const DefaultTickInterval = 500 * time.Millisecond
+
+// EmptyKettleGrams is what the kettle weighs with no water in it
+const EmptyKettleGrams = 40.0
+
+// HeaterPowerOutput is the analog output setting the kettle heater power, 0-5V being 0-100%
+const HeaterPowerOutput = 1
+
+// FullHeaterPowerVolts is the heater power output voltage at full power, which it starts at
+const FullHeaterPowerVolts = 5.0
+
+// ErrInterlock is returned by SetDigitalOutput when safe mode refuses a dangerous output
+var ErrInterlock = errors.New("safety interlock")
+
+// Config holds the settings of an IOBank simulation
+// The physics rates are per second so any tick interval simulates the same behaviour,
+// shorter intervals just update the inputs more smoothly
+// SafeMode enforces interlocks on dangerous outputs, by default outputs behave like dumb hardware
+type Config struct {
+	TickInterval time.Duration
+	SafeMode     bool
+}
+
+// DefaultConfig returns the configuration used by NewIOBank
+func DefaultConfig() Config {
+	return Config{TickInterval: DefaultTickInterval}
+}
+
 // IOBank represents a simulated I/O bank with digital and analog ports
 type IOBank struct {
 	mu sync.RWMutex
@@ -36,13 +66,37 @@ type IOBank struct {
 	// Simulation parameters
 	simulationRunning bool
 	stopChan          chan bool
+	tickInterval      time.Duration
+	safeMode          bool
+
+	// onChange is called when the simulation or a caller changes an IO value
+	onChange func()
 }
 
-// NewIOBank creates a new I/O bank simulation
+// ioState is a comparable copy of the IO values, used to detect changes
+type ioState struct {
+	digitalInputs  [8]bool
+	digitalOutputs [16]bool
+	analogInputs   [4]float64
+	analogOutputs  [4]float64
+}
+
+// NewIOBank creates a new I/O bank simulation with the default configuration
 func NewIOBank() *IOBank {
+	return NewIOBankWithConfig(DefaultConfig())
+}
+
+// NewIOBankWithConfig creates a new I/O bank simulation with the given configuration
+// A zero tick interval uses DefaultTickInterval
+func NewIOBankWithConfig(cfg Config) *IOBank {
+	if cfg.TickInterval <= 0 {
+		cfg.TickInterval = DefaultTickInterval
+	}
 	bank := &IOBank{
-		stopChan:    make(chan bool),
-		mcpMessages: make([]MCPMessage, 0),
+		stopChan:     make(chan bool),
+		mcpMessages:  make([]MCPMessage, 0),
+		tickInterval: cfg.TickInterval,
+		safeMode:     cfg.SafeMode,
 	}
 
 	// Initialize with realistic starting values for inputs
@@ -59,6 +113,9 @@ func NewIOBank() *IOBank {
 	bank.analogInputs[2] = 0.0  // Cup Weight: 0g (no cup present initially, 0-5V = 0-1000g)
 	bank.analogInputs[3] = 0.1  // Kettle Weight: 40g (empty kettle, 0.1V = 40g if 5V = 2000g)
 
+	// The heater runs at full power unless turned down
+	bank.analogOutputs[HeaterPowerOutput] = FullHeaterPowerVolts
+
 	logger.Info("IOBank initialized with realistic starting values")
 	return bank
 }
@@ -74,7 +131,7 @@ func (io *IOBank) StartSimulation() {
 	io.mu.Unlock()
 
 	go io.simulationLoop()
-	logger.Info("IOBank simulation started")
+	logger.Info("IOBank simulation started with a %v tick", io.tickInterval)
 }
 
 // StopSimulation stops the background simulation
@@ -93,7 +150,7 @@ func (io *IOBank) StopSimulation() {
 
 // simulationLoop runs in the background and periodically updates input values
 func (io *IOBank) simulationLoop() {
-	ticker := time.NewTicker(500 * time.Millisecond)
+	ticker := time.NewTicker(io.tickInterval)
 	defer ticker.Stop()
 
 	for {
@@ -101,26 +158,75 @@ func (io *IOBank) simulationLoop() {
 		case <-io.stopChan:
 			return
 		case <-ticker.C:
-			io.updateInputs()
+			io.Step()
 		}
 	}
 }
 
+// Step advances the simulation by a single tick, as the running simulation does each tick interval
+func (io *IOBank) Step() {
+	io.changing(func() error {
+		io.updateInputs()
+		return nil
+	})
+}
+
+// OnChange sets a function to be called whenever an IO value changes, either through the
+// simulation or a call such as SetDigitalOutput. It is called without the lock held so it
+// may read the bank's state. Passing nil removes the function
+func (io *IOBank) OnChange(fn func()) {
+	io.mu.Lock()
+	defer io.mu.Unlock()
+	io.onChange = fn
+}
+
+// snapshot copies the IO values, must be called with the lock held
+func (io *IOBank) snapshot() ioState {
+	return ioState{
+		digitalInputs:  io.digitalInputs,
+		digitalOutputs: io.digitalOutputs,
+		analogInputs:   io.analogInputs,
+		analogOutputs:  io.analogOutputs,
+	}
+}
+
+// changing runs fn, which takes the lock itself, and calls the change function
+// if fn changed any IO value
+func (io *IOBank) changing(fn func() error) error {
+	io.mu.RLock()
+	before := io.snapshot()
+	io.mu.RUnlock()
+
+	err := fn()
+
+	io.mu.RLock()
+	changed := io.snapshot() != before
+	onChange := io.onChange
+	io.mu.RUnlock()
+
+	if changed && onChange != nil {
+		onChange()
+	}
+	return err
+}
+
 // updateInputs simulates tea-making machine physics
 func (io *IOBank) updateInputs() {
 	io.mu.Lock()
 	defer io.mu.Unlock()
 
-	// Simulation runs every 0.5 seconds, so calculate rates per 0.5-second interval
-	const updateInterval = 0.5 // seconds
+	// Rates are per second (or minute) so scale them by the length of a tick
+	updateInterval := io.tickInterval.Seconds()
 	
 	// Tea-making machine physics simulation
 	
 	// AI1 = Kettle Water Temperature (0-5V representing 0-100°C, so 1V = 20°C)
 	// DO3 = Kettle Power Relay (heating element)
+	// AO1 = Kettle Heater Power (0-5V representing 0-100%)
 	if io.digitalOutputs[3] { // Kettle heating
-		// Heat at 100°C/min regardless of water level - hardware doesn't know better
-		tempIncrease := 100.0 * (updateInterval / 60.0) // degrees per update
+		// Heat at up to 100°C/min regardless of water level - hardware doesn't know better
+		power := io.analogOutputs[HeaterPowerOutput] / FullHeaterPowerVolts
+		tempIncrease := 100.0 * power * (updateInterval / 60.0) // degrees per update
 		currentTempC := io.analogInputs[1] * 20.0 // Convert V to °C (1V = 20°C)
 		newTempC := currentTempC + tempIncrease
 		if newTempC > 100.0 { // Cap at boiling point
@@ -250,12 +356,25 @@ func (io *IOBank) GetAllDigitalInputs() [8]bool {
 // Digital Output Methods
 // SetDigitalOutput sets a digital output and handles special logic for dispensers
 func (io *IOBank) SetDigitalOutput(pin int, value bool) error {
+	return io.changing(func() error {
+		return io.setDigitalOutput(pin, value)
+	})
+}
+
+func (io *IOBank) setDigitalOutput(pin int, value bool) error {
 	if pin < 0 || pin > 15 {
 		return fmt.Errorf("digital output pin %d out of range (0-15)", pin)
 	}
 
 	io.mu.Lock()
 	defer io.mu.Unlock()
+
+	if io.safeMode && value {
+		if err := io.checkInterlocks(pin); err != nil {
+			logger.Warn("Refused to set digital output %d: %v", pin, err)
+			return err
+		}
+	}
 	
 	// Handle special dispenser logic
 	switch pin {
@@ -404,6 +523,42 @@ func (io *IOBank) SetDigitalOutput(pin int, value bool) error {
 	return nil
 }
 
+// SetSafeMode enables or disables the safety interlocks
+func (io *IOBank) SetSafeMode(enabled bool) {
+	io.mu.Lock()
+	defer io.mu.Unlock()
+	io.safeMode = enabled
+	logger.Info("Safe mode set to %v", enabled)
+}
+
+// SafeMode returns true if the safety interlocks are enforced
+func (io *IOBank) SafeMode() bool {
+	io.mu.RLock()
+	defer io.mu.RUnlock()
+	return io.safeMode
+}
+
+// checkInterlocks returns an error wrapping ErrInterlock if switching on the output would be
+// dangerous in the current state. Switching an output off is always allowed.
+// Must be called with the lock held
+func (io *IOBank) checkInterlocks(pin int) error {
+	kettleG := io.analogInputs[3] * 400.0 // Convert V to grams (1V = 400g)
+	switch pin {
+	case 3: // Kettle Power Relay
+		if kettleG <= EmptyKettleGrams {
+			return fmt.Errorf("%w: kettle heater (DO3) cannot be switched on while the kettle is empty", ErrInterlock)
+		}
+	case 2: // Kettle Water Outlet Valve
+		if kettleG <= EmptyKettleGrams {
+			return fmt.Errorf("%w: kettle outlet valve (DO2) cannot be opened while the kettle is empty", ErrInterlock)
+		}
+		if !io.digitalInputs[1] {
+			return fmt.Errorf("%w: kettle outlet valve (DO2) cannot be opened while no cup is present", ErrInterlock)
+		}
+	}
+	return nil
+}
+
 func (io *IOBank) GetDigitalOutput(pin int) (bool, error) {
 	if pin < 0 || pin > 15 {
 		return false, fmt.Errorf("digital output pin %d out of range (0-15)", pin)
@@ -445,6 +600,12 @@ func (io *IOBank) GetAllAnalogInputs() [4]float64 {
 
 // Analog Output Methods
 func (io *IOBank) SetAnalogOutput(pin int, value float64) error {
+	return io.changing(func() error {
+		return io.setAnalogOutput(pin, value)
+	})
+}
+
+func (io *IOBank) setAnalogOutput(pin int, value float64) error {
 	if pin < 0 || pin > 3 {
 		return fmt.Errorf("analog output pin %d out of range (0-3)", pin)
 	}
@@ -481,6 +642,10 @@ func (io *IOBank) GetAllAnalogOutputs() [4]float64 {
 
 // Reset resets the I/O bank to initial values
 func (io *IOBank) Reset() error {
+	return io.changing(io.reset)
+}
+
+func (io *IOBank) reset() error {
 	io.mu.Lock()
 	defer io.mu.Unlock()
 
@@ -494,10 +659,11 @@ func (io *IOBank) Reset() error {
 		io.digitalOutputs[i] = false
 	}
 
-	// Reset all analog outputs to 0V
+	// Reset all analog outputs to 0V, apart from the heater power which returns to full
 	for i := 0; i < 4; i++ {
 		io.analogOutputs[i] = 0.0
 	}
+	io.analogOutputs[HeaterPowerOutput] = FullHeaterPowerVolts
 
 	// Reset analog inputs to initial realistic values
 	io.analogInputs[0] = 0.0  // AI 00: 0V
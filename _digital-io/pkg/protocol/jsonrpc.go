@@ -28,6 +28,18 @@ const (
 	MethodInvokeTool    MethodType = "invoke_tool"
 )
 
+// Resource methods
+const (
+	MethodResourcesRead        MethodType = "resources/read"
+	MethodResourcesSubscribe   MethodType = "resources/subscribe"
+	MethodResourcesUnsubscribe MethodType = "resources/unsubscribe"
+)
+
+// Notification methods
+const (
+	MethodResourcesUpdated MethodType = "notifications/resources/updated"
+)
+
 // Version is the JSON-RPC protocol version
 const JsonRpcVersion = "2.0"
 
@@ -49,9 +61,9 @@ type JsonRpcResponse struct {
 
 // Error represents a JSON-RPC 2.0 error object
 type JsonRpcError struct {
-	Code    int `json:"code"`
+	Code    int    `json:"code"`
 	Message string `json:"message"`
-	Data    any `json:"data,omitempty"`
+	Data    any    `json:"data,omitempty"`
 }
 
 // Tool property definition
@@ -72,9 +84,44 @@ type InputSchema struct {
 
 // Tool represents a tool that can be invoked
 type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema InputSchema `json:"inputSchema"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	InputSchema InputSchema      `json:"inputSchema"`
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are hints to the client about how a tool behaves
+// so that it can, for example, warn the user before running a destructive tool
+// https://modelcontextprotocol.io/docs/concepts/tools#tool-annotations
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// ReadOnlyAnnotations returns the annotations for a tool which does not modify its environment
+func ReadOnlyAnnotations(title string, openWorld bool) *ToolAnnotations {
+	readOnly := true
+	return &ToolAnnotations{
+		Title:         title,
+		ReadOnlyHint:  &readOnly,
+		OpenWorldHint: &openWorld,
+	}
+}
+
+// MutatingAnnotations returns the annotations for a tool which modifies its environment
+// destructive should be true if the tool may overwrite or remove existing state
+func MutatingAnnotations(title string, destructive bool, idempotent bool, openWorld bool) *ToolAnnotations {
+	readOnly := false
+	return &ToolAnnotations{
+		Title:           title,
+		ReadOnlyHint:    &readOnly,
+		DestructiveHint: &destructive,
+		IdempotentHint:  &idempotent,
+		OpenWorldHint:   &openWorld,
+	}
 }
 
 // Standard error codes defined by the JSON-RPC 2.0 specification
@@ -108,6 +155,11 @@ func NewJsonRpcRequest(method string, params interface{}, id interface{}) (*Json
 	}, nil
 }
 
+// NewJsonRpcNotification creates a new JSON-RPC 2.0 notification (a request without an ID)
+func NewJsonRpcNotification(method string, params interface{}) (*JsonRpcRequest, error) {
+	return NewJsonRpcRequest(method, params, nil)
+}
+
 // NewResponse creates a new JSON-RPC 2.0 success response
 func NewJsonRpcResponse(result any, id any) (*JsonRpcResponse, error) {
 	var resultJSON json.RawMessage
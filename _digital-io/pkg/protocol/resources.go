@@ -0,0 +1,23 @@
+package protocol
+
+// Resource describes data the server exposes for clients to read
+// https://modelcontextprotocol.io/docs/concepts/resources
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents is the content of a resource returned by resources/read
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// ResourceParams are the parameters of resources/read, resources/subscribe and
+// resources/unsubscribe requests, and of resources/updated notifications
+type ResourceParams struct {
+	URI string `json:"uri"`
+}
@@ -4,26 +4,114 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/richard-senior/mcp/_digital-io/internal/config"
+	"github.com/richard-senior/mcp/_digital-io/internal/logger"
 )
 
 // HTTPClient provides methods to interact with the HTTP server
 type HTTPClient struct {
-	baseURL string
-	client  *http.Client
+	baseURL    string
+	token      string
+	client     *http.Client
+	maxRetries int
+	baseDelay  time.Duration
+	backoff    BackoffStrategy
+
+	healthMu sync.Mutex
+	health   ClientHealth
+}
+
+// ClientHealth describes how well the client has been reaching the server, so that tools
+// can report degraded connectivity. Connected is false once a request has failed every retry
+// and becomes true again when a request gets through
+type ClientHealth struct {
+	Connected           bool   `json:"connected"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LastError           string `json:"last_error,omitempty"`
+	LastSuccess         string `json:"last_success,omitempty"`
 }
 
 // NewHTTPClient creates a new HTTP client for the I/O server
-func NewHTTPClient(baseURL string) *HTTPClient {
-	return &HTTPClient{
+// The bearer token, if any, is taken from the same environment variable as the server
+// By default requests which can't reach the server are retried twice with a linear backoff
+// from one second, so that the client reconnects if the server is restarted
+func NewHTTPClient(baseURL string, opts ...ClientOption) *HTTPClient {
+	c := &HTTPClient{
 		baseURL: baseURL,
+		token:   config.GetAPIToken(),
 		client: &http.Client{
-			Timeout: 10 * time.Second, // Increased timeout
+			Timeout: DefaultTimeout,
 		},
+		maxRetries: DefaultMaxRetries,
+		baseDelay:  DefaultBaseDelay,
+		backoff:    LinearBackoff,
+		health:     ClientHealth{Connected: true},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// get performs an authenticated GET request against the server
+func (c *HTTPClient) get(path string) (*http.Response, error) {
+	return c.do(http.MethodGet, path, nil)
+}
+
+// post performs an authenticated JSON POST request against the server
+func (c *HTTPClient) post(path string, body []byte) (*http.Response, error) {
+	return c.do(http.MethodPost, path, body)
+}
+
+// do sends a request, retrying according to the client's connection policy while the server
+// is unreachable. Responses, including error statuses, are returned straight away as
+// retrying won't help
+func (c *HTTPClient) do(method string, path string, body []byte) (*http.Response, error) {
+	var err error
+	for retry := 0; retry <= c.maxRetries; retry++ {
+		if retry > 0 {
+			delay := c.backoff(retry, c.baseDelay)
+			logger.Warn("%s %s failed (attempt %d/%d), retrying in %v: %v", method, path, retry, c.maxRetries+1, delay, err)
+			time.Sleep(delay)
+		}
+		var resp *http.Response
+		resp, err = c.send(method, path, body)
+		if err == nil {
+			c.recordSuccess()
+			return resp, nil
+		}
+		if !c.isUnreachable(err) {
+			return nil, err
+		}
+	}
+	c.recordFailure(err)
+	return nil, err
+}
+
+// send builds and sends a single request, adding the bearer token when configured
+func (c *HTTPClient) send(method string, path string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.client.Do(req)
 }
 
 // isServerDown checks if the error indicates the server is down
@@ -40,32 +128,63 @@ func (c *HTTPClient) isServerDown(err error) bool {
 		   strings.Contains(errStr, "broken pipe")
 }
 
+// isUnreachable checks if the error means no response could be had from the server at all
+func (c *HTTPClient) isUnreachable(err error) bool {
+	if c.isServerDown(err) {
+		return true
+	}
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// recordSuccess notes that the server has been reached
+func (c *HTTPClient) recordSuccess() {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	if !c.health.Connected {
+		logger.Info("Reconnected to the Digital I/O server at %s", c.baseURL)
+	}
+	c.health = ClientHealth{
+		Connected:   true,
+		LastSuccess: time.Now().Format(time.RFC3339),
+	}
+}
+
+// recordFailure notes that a request couldn't reach the server despite retrying
+func (c *HTTPClient) recordFailure(err error) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.health.Connected = false
+	c.health.ConsecutiveFailures++
+	c.health.LastError = err.Error()
+}
+
+// Health returns the client's current view of its connection to the server
+func (c *HTTPClient) Health() ClientHealth {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	return c.health
+}
+
 // wrapError creates a user-friendly error message
 func (c *HTTPClient) wrapError(operation string, err error) error {
 	if c.isServerDown(err) {
-		return fmt.Errorf("Digital I/O server is not running or not accessible. Please start the HTTP server first (run: ./digital-io-server). Original error: %v", err)
+		return &ServerUnreachableError{Operation: operation, Err: err}
 	}
 	
 	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-		return fmt.Errorf("Digital I/O server is not responding (timeout). The server may be overloaded or stuck. Original error: %v", err)
+		return &ServerUnreachableError{Operation: operation, Timeout: true, Err: err}
 	}
 	
 	return fmt.Errorf("%s failed: %v", operation, err)
 }
 
-// HealthCheck performs a basic health check on the server
+// HealthCheck checks the server is up by fetching the system status, retrying according to
+// the client's connection policy while the server is unreachable.
+// A ServerResponseError is returned straight away as retrying won't help
 func (c *HTTPClient) HealthCheck() error {
-	resp, err := c.client.Get(fmt.Sprintf("%s/status", c.baseURL))
-	if err != nil {
-		return c.wrapError("Health check", err)
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Digital I/O server returned HTTP %d - server may be in an error state", resp.StatusCode)
-	}
-	
-	return nil
+	_, err := c.GetSystemStatus()
+	return err
 }
 
 // RecordMCPMessage sends an MCP message to the HTTP server for recording
@@ -80,14 +199,14 @@ func (c *HTTPClient) RecordMCPMessage(toolName, message string) error {
 		return fmt.Errorf("failed to marshal MCP message: %v", err)
 	}
 	
-	resp, err := c.client.Post(fmt.Sprintf("%s/mcp/message", c.baseURL), "application/json", bytes.NewBuffer(jsonData))
+	resp, err := c.post("/mcp/message", jsonData)
 	if err != nil {
 		return c.wrapError("Record MCP message", err)
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP error recording MCP message: %d", resp.StatusCode)
+		return &ServerResponseError{Operation: "recording MCP message", StatusCode: resp.StatusCode}
 	}
 	
 	return nil
@@ -95,14 +214,14 @@ func (c *HTTPClient) RecordMCPMessage(toolName, message string) error {
 
 // GetDigitalInput reads a digital input via HTTP
 func (c *HTTPClient) GetDigitalInput(pin int) (bool, error) {
-	resp, err := c.client.Get(fmt.Sprintf("%s/digital/input/%d", c.baseURL, pin))
+	resp, err := c.get(fmt.Sprintf("/digital/input/%d", pin))
 	if err != nil {
 		return false, c.wrapError(fmt.Sprintf("Get digital input pin %d", pin), err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("Digital I/O server returned HTTP %d for digital input pin %d", resp.StatusCode, pin)
+		return false, &ServerResponseError{Operation: fmt.Sprintf("digital input pin %d", pin), StatusCode: resp.StatusCode}
 	}
 
 	var result map[string]interface{}
@@ -129,18 +248,16 @@ func (c *HTTPClient) SetDigitalOutput(pin int, value bool) error {
 		return fmt.Errorf("failed to marshal request: %v", err)
 	}
 
-	resp, err := c.client.Post(
-		fmt.Sprintf("%s/digital/output/%d", c.baseURL, pin),
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	resp, err := c.post(fmt.Sprintf("/digital/output/%d", pin), jsonData)
 	if err != nil {
 		return c.wrapError(fmt.Sprintf("Set digital output pin %d", pin), err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Digital I/O server returned HTTP %d for setting digital output pin %d", resp.StatusCode, pin)
+		// the server explains refusals such as safety interlocks in the body
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &ServerResponseError{Operation: fmt.Sprintf("setting digital output pin %d", pin), StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
 	}
 
 	return nil
@@ -148,14 +265,14 @@ func (c *HTTPClient) SetDigitalOutput(pin int, value bool) error {
 
 // GetDigitalOutput reads a digital output via HTTP
 func (c *HTTPClient) GetDigitalOutput(pin int) (bool, error) {
-	resp, err := c.client.Get(fmt.Sprintf("%s/digital/output/%d", c.baseURL, pin))
+	resp, err := c.get(fmt.Sprintf("/digital/output/%d", pin))
 	if err != nil {
 		return false, c.wrapError(fmt.Sprintf("Get digital output pin %d", pin), err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("Digital I/O server returned HTTP %d for digital output pin %d", resp.StatusCode, pin)
+		return false, &ServerResponseError{Operation: fmt.Sprintf("digital output pin %d", pin), StatusCode: resp.StatusCode}
 	}
 
 	var result map[string]interface{}
@@ -173,14 +290,14 @@ func (c *HTTPClient) GetDigitalOutput(pin int) (bool, error) {
 
 // GetAnalogInput reads an analog input via HTTP
 func (c *HTTPClient) GetAnalogInput(pin int) (float64, error) {
-	resp, err := c.client.Get(fmt.Sprintf("%s/analog/input/%d", c.baseURL, pin))
+	resp, err := c.get(fmt.Sprintf("/analog/input/%d", pin))
 	if err != nil {
 		return 0, c.wrapError(fmt.Sprintf("Get analog input pin %d", pin), err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("Digital I/O server returned HTTP %d for analog input pin %d", resp.StatusCode, pin)
+		return 0, &ServerResponseError{Operation: fmt.Sprintf("analog input pin %d", pin), StatusCode: resp.StatusCode}
 	}
 
 	var result map[string]interface{}
@@ -212,18 +329,14 @@ func (c *HTTPClient) SetAnalogOutput(pin int, value float64) error {
 		return fmt.Errorf("failed to marshal request: %v", err)
 	}
 
-	resp, err := c.client.Post(
-		fmt.Sprintf("%s/analog/output/%d", c.baseURL, pin),
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	resp, err := c.post(fmt.Sprintf("/analog/output/%d", pin), jsonData)
 	if err != nil {
 		return c.wrapError(fmt.Sprintf("Set analog output pin %d", pin), err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Digital I/O server returned HTTP %d for setting analog output pin %d", resp.StatusCode, pin)
+		return &ServerResponseError{Operation: fmt.Sprintf("setting analog output pin %d", pin), StatusCode: resp.StatusCode}
 	}
 
 	return nil
@@ -231,14 +344,14 @@ func (c *HTTPClient) SetAnalogOutput(pin int, value float64) error {
 
 // GetAnalogOutput reads an analog output via HTTP
 func (c *HTTPClient) GetAnalogOutput(pin int) (float64, error) {
-	resp, err := c.client.Get(fmt.Sprintf("%s/analog/output/%d", c.baseURL, pin))
+	resp, err := c.get(fmt.Sprintf("/analog/output/%d", pin))
 	if err != nil {
 		return 0, c.wrapError(fmt.Sprintf("Get analog output pin %d", pin), err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("Digital I/O server returned HTTP %d for analog output pin %d", resp.StatusCode, pin)
+		return 0, &ServerResponseError{Operation: fmt.Sprintf("analog output pin %d", pin), StatusCode: resp.StatusCode}
 	}
 
 	var result map[string]interface{}
@@ -261,14 +374,14 @@ func (c *HTTPClient) GetAnalogOutput(pin int) (float64, error) {
 
 // GetSystemStatus gets the complete system status via HTTP
 func (c *HTTPClient) GetSystemStatus() (map[string]interface{}, error) {
-	resp, err := c.client.Get(fmt.Sprintf("%s/status", c.baseURL))
+	resp, err := c.get("/status")
 	if err != nil {
 		return nil, c.wrapError("Get system status", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Digital I/O server returned HTTP %d for system status", resp.StatusCode)
+		return nil, &ServerResponseError{Operation: "system status", StatusCode: resp.StatusCode}
 	}
 
 	var result map[string]interface{}
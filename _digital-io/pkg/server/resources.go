@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/richard-senior/mcp/_digital-io/internal/logger"
+	"github.com/richard-senior/mcp/_digital-io/pkg/protocol"
+)
+
+// BankStateURI identifies the resource holding the current state of the IO bank
+const BankStateURI = "io://bank/state"
+
+// statePollInterval is how often the HTTP server is polled for changes to the IO bank
+// while a client is subscribed in HTTP client mode, matching the simulation's default tick
+const statePollInterval = 500 * time.Millisecond
+
+// bankStateResource describes the IO bank state resource
+func bankStateResource() protocol.Resource {
+	return protocol.Resource{
+		URI:         BankStateURI,
+		Name:        "IO Bank State",
+		Description: "The current state of all digital and analog I/O pins of the tea machine, with labels and engineering units. Subscribe to be notified as it changes",
+		MimeType:    "application/json",
+	}
+}
+
+// handleResourcesList handles the resources/list method
+func (s *Server) handleResourcesList(params interface{}) (interface{}, error) {
+	logger.Info("Handling resources/list request")
+	return map[string]interface{}{
+		"resources": []protocol.Resource{bankStateResource()},
+	}, nil
+}
+
+// handleResourcesRead handles the resources/read method
+func (s *Server) handleResourcesRead(params interface{}) (interface{}, error) {
+	resourceParams, err := decodeResourceParams(params)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Handling resources/read request for", resourceParams.URI)
+
+	status, err := s.handleGetSystemStatus(nil)
+	if err != nil {
+		return nil, err
+	}
+	text, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal IO bank state: %v", err)
+	}
+
+	return map[string]interface{}{
+		"contents": []protocol.ResourceContents{{
+			URI:      BankStateURI,
+			MimeType: "application/json",
+			Text:     string(text),
+		}},
+	}, nil
+}
+
+// handleResourcesSubscribe handles the resources/subscribe method
+// In HTTP client mode the HTTP server is polled for changes while the client is subscribed
+func (s *Server) handleResourcesSubscribe(params interface{}) (interface{}, error) {
+	if _, err := decodeResourceParams(params); err != nil {
+		return nil, err
+	}
+	logger.Info("Client subscribed to", BankStateURI)
+
+	s.resourceMu.Lock()
+	s.subscribed = true
+	startPolling := s.httpClient != nil && !s.polling
+	if startPolling {
+		s.polling = true
+	}
+	s.resourceMu.Unlock()
+
+	if startPolling {
+		go s.pollBankState()
+	}
+	return map[string]interface{}{}, nil
+}
+
+// handleResourcesUnsubscribe handles the resources/unsubscribe method
+func (s *Server) handleResourcesUnsubscribe(params interface{}) (interface{}, error) {
+	if _, err := decodeResourceParams(params); err != nil {
+		return nil, err
+	}
+	logger.Info("Client unsubscribed from", BankStateURI)
+
+	s.resourceMu.Lock()
+	s.subscribed = false
+	s.resourceMu.Unlock()
+	return map[string]interface{}{}, nil
+}
+
+// isSubscribed returns true if the client has subscribed to the IO bank state
+func (s *Server) isSubscribed() bool {
+	s.resourceMu.Lock()
+	defer s.resourceMu.Unlock()
+	return s.subscribed
+}
+
+// handleBankChange is called by the IO bank whenever its state changes
+func (s *Server) handleBankChange() {
+	if s.isSubscribed() {
+		s.notifyBankStateUpdated()
+	}
+}
+
+// notifyBankStateUpdated tells the client the IO bank state resource has changed
+func (s *Server) notifyBankStateUpdated() {
+	notification, err := protocol.NewJsonRpcNotification(string(protocol.MethodResourcesUpdated), protocol.ResourceParams{URI: BankStateURI})
+	if err != nil {
+		logger.Error("Failed to create resource updated notification: %v", err)
+		return
+	}
+	if err := s.transport.WriteNotification(notification); err != nil {
+		logger.Error("Failed to send resource updated notification: %v", err)
+	}
+}
+
+// pollBankState polls the HTTP server for changes to the IO values until the client unsubscribes
+func (s *Server) pollBankState() {
+	ticker := time.NewTicker(statePollInterval)
+	defer ticker.Stop()
+
+	var last string
+	for range ticker.C {
+		s.resourceMu.Lock()
+		if !s.subscribed {
+			s.polling = false
+			s.resourceMu.Unlock()
+			return
+		}
+		s.resourceMu.Unlock()
+
+		status, err := s.httpClient.GetSystemStatus()
+		if err != nil {
+			logger.Warn("Failed to poll IO bank state: %v", err)
+			continue
+		}
+		current := ioValuesKey(status)
+		if last != "" && current != last {
+			s.notifyBankStateUpdated()
+		}
+		last = current
+	}
+}
+
+// ioValuesKey renders just the IO values of a status so that changes to them can be detected
+// without being confused by fields such as the MCP message log
+func ioValuesKey(status map[string]interface{}) string {
+	values, _ := json.Marshal([]interface{}{
+		status["digital_inputs"],
+		status["digital_outputs"],
+		status["analog_inputs"],
+		status["analog_outputs"],
+	})
+	return string(values)
+}
+
+// decodeResourceParams decodes and checks the URI of a resource request
+func decodeResourceParams(params interface{}) (protocol.ResourceParams, error) {
+	var resourceParams protocol.ResourceParams
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return resourceParams, fmt.Errorf("failed to marshal params: %v", err)
+	}
+	if err := json.Unmarshal(paramsBytes, &resourceParams); err != nil {
+		return resourceParams, fmt.Errorf("invalid resource parameters: %v", err)
+	}
+	if resourceParams.URI != BankStateURI {
+		return resourceParams, fmt.Errorf("resource not found: %s", resourceParams.URI)
+	}
+	return resourceParams, nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default connection policy, three attempts one then two seconds apart
+const (
+	DefaultMaxRetries = 2
+	DefaultBaseDelay  = time.Second
+	DefaultTimeout    = 10 * time.Second
+)
+
+// BackoffStrategy returns how long to wait before the given retry (starting at 1)
+type BackoffStrategy func(retry int, baseDelay time.Duration) time.Duration
+
+// ConstantBackoff waits baseDelay before every retry
+func ConstantBackoff(retry int, baseDelay time.Duration) time.Duration {
+	return baseDelay
+}
+
+// LinearBackoff waits baseDelay multiplied by the retry number
+func LinearBackoff(retry int, baseDelay time.Duration) time.Duration {
+	return time.Duration(retry) * baseDelay
+}
+
+// ExponentialBackoff doubles the wait after each retry
+func ExponentialBackoff(retry int, baseDelay time.Duration) time.Duration {
+	return baseDelay << (retry - 1)
+}
+
+// ClientOption configures an HTTPClient
+type ClientOption func(*HTTPClient)
+
+// WithMaxRetries sets how many times a failed connection is retried after the first attempt
+func WithMaxRetries(retries int) ClientOption {
+	return func(c *HTTPClient) {
+		if retries >= 0 {
+			c.maxRetries = retries
+		}
+	}
+}
+
+// WithBaseDelay sets the delay the backoff strategy starts from
+func WithBaseDelay(delay time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		c.baseDelay = delay
+	}
+}
+
+// WithBackoff sets the strategy used to space out retries
+func WithBackoff(backoff BackoffStrategy) ClientOption {
+	return func(c *HTTPClient) {
+		if backoff != nil {
+			c.backoff = backoff
+		}
+	}
+}
+
+// WithTimeout sets the timeout of each individual request
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		c.client.Timeout = timeout
+	}
+}
+
+// ServerUnreachableError is returned when no response could be had from the server at all,
+// because it isn't running, can't be reached or didn't answer in time
+type ServerUnreachableError struct {
+	Operation string
+	Timeout   bool
+	Err       error
+}
+
+func (e *ServerUnreachableError) Error() string {
+	if e.Timeout {
+		return fmt.Sprintf("Digital I/O server is not responding (timeout). The server may be overloaded or stuck. Original error: %v", e.Err)
+	}
+	return fmt.Sprintf("Digital I/O server is not running or not accessible. Please start the HTTP server first (run: ./digital-io-server). Original error: %v", e.Err)
+}
+
+func (e *ServerUnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// ServerResponseError is returned when the server was reached but responded with an error status
+// Message holds the reason given by the server, if any
+type ServerResponseError struct {
+	Operation  string
+	StatusCode int
+	Message    string
+}
+
+func (e *ServerResponseError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Digital I/O server returned HTTP %d for %s: %s", e.StatusCode, e.Operation, e.Message)
+	}
+	return fmt.Sprintf("Digital I/O server returned HTTP %d for %s", e.StatusCode, e.Operation)
+}
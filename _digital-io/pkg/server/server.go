@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/richard-senior/mcp/_digital-io/internal/iobank"
@@ -21,6 +22,11 @@ type Server struct {
 	tools      []protocol.Tool
 	ioBank     *iobank.IOBank
 	httpClient *HTTPClient // For HTTP client mode
+
+	// resourceMu guards the IO bank state resource subscription
+	resourceMu sync.Mutex
+	subscribed bool
+	polling    bool
 }
 
 // HandlerFunc is a function that handles an MCP request
@@ -39,6 +45,9 @@ func NewServer(t transport.Transport, bank *iobank.IOBank) *Server {
 	// Register default tools
 	server.RegisterDefaultTools()
 
+	// Tell subscribed clients when the simulation or a tool changes the IO
+	bank.OnChange(server.handleBankChange)
+
 	return server
 }
 
@@ -83,6 +92,7 @@ func (s *Server) RegisterDefaultTools() {
 	// Register analog output tools
 	s.RegisterTool(s.createSetAnalogOutputTool(), s.handleSetAnalogOutput)
 	s.RegisterTool(s.createGetAnalogOutputTool(), s.handleGetAnalogOutput)
+	s.RegisterTool(s.createGetAnalogValuesTool(), s.handleGetAnalogValues)
 
 	// Register system status tool
 	s.RegisterTool(s.createGetSystemStatusTool(), s.handleGetSystemStatus)
@@ -97,6 +107,10 @@ func (s *Server) Start() error {
 	s.handlers[string(protocol.MethodInitialized)] = s.handleInitialized
 	s.handlers[string(protocol.MethodToolsList)] = s.handleToolsList
 	s.handlers[string(protocol.MethodToolsCall)] = s.handleToolsCall
+	s.handlers[string(protocol.MethodResourcesList)] = s.handleResourcesList
+	s.handlers[string(protocol.MethodResourcesRead)] = s.handleResourcesRead
+	s.handlers[string(protocol.MethodResourcesSubscribe)] = s.handleResourcesSubscribe
+	s.handlers[string(protocol.MethodResourcesUnsubscribe)] = s.handleResourcesUnsubscribe
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -249,6 +263,9 @@ func (s *Server) handleInitialize(params interface{}) (interface{}, error) {
 		ProtocolVersion: "2024-11-05",
 		Capabilities: map[string]any{
 			"tools": struct{}{},
+			"resources": map[string]any{
+				"subscribe": true,
+			},
 		},
 		ServerInfo: struct {
 			Name        string `json:"name"`
@@ -298,6 +315,7 @@ func (s *Server) handleInitialize(params interface{}) (interface{}, error) {
 				analog_input pin 1: Kettle Water Temperature (0-5v = 0-100 degrees c)
 				analog_input pin 2: Cup Weight (0-5v = 0-1000g)
 				analog_input pin 3: Kettle Weight (0-5v = 0-2000g)
+				analog_output pin 1: Kettle Heater Power (0-5v = 0-100%, starts at 5v) sets how fast the kettle heats while the Power Relay is on
 		`,
 		},
 	}
@@ -389,7 +407,8 @@ func (s *Server) formatToolResult(toolName string, result interface{}) string {
 			return fmt.Sprintf("Pin %v: %v", pin, value)
 		}
 	case strings.Contains(toolName, "analog"):
-		if data, ok := result.(map[string]interface{}); ok {
+		// results covering every pin, such as get_analog_values, are returned as JSON
+		if data, ok := result.(map[string]interface{}); ok && data["pin"] != nil {
 			pin := data["pin"]
 			value := data["value"]
 			unit := data["unit"]
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/richard-senior/mcp/_digital-io/internal/api"
 	"github.com/richard-senior/mcp/_digital-io/internal/config"
 	"github.com/richard-senior/mcp/_digital-io/pkg/protocol"
 )
@@ -26,6 +27,7 @@ func (s *Server) createGetDigitalInputTool() protocol.Tool {
 			},
 			Required: []string{"pin"},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("Read Digital Input", false),
 	}
 }
 
@@ -45,6 +47,7 @@ func (s *Server) createSetDigitalOutputTool() protocol.Tool {
 			},
 			Required: []string{"pin"},
 		},
+		Annotations: protocol.MutatingAnnotations("Set Digital Output", true, true, false),
 	}
 }
 
@@ -64,6 +67,7 @@ func (s *Server) createUnsetDigitalOutputTool() protocol.Tool {
 			},
 			Required: []string{"pin"},
 		},
+		Annotations: protocol.MutatingAnnotations("Unset Digital Output", true, true, false),
 	}
 }
 
@@ -83,6 +87,7 @@ func (s *Server) createGetDigitalOutputTool() protocol.Tool {
 			},
 			Required: []string{"pin"},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("Read Digital Output", false),
 	}
 }
 
@@ -102,11 +107,10 @@ func (s *Server) createGetAnalogInputTool() protocol.Tool {
 			},
 			Required: []string{"pin"},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("Read Analog Input", false),
 	}
 }
 
-
-
 func (s *Server) createSetAnalogOutputTool() protocol.Tool {
 	return protocol.Tool{
 		Name:        "set_analog_output",
@@ -127,6 +131,7 @@ func (s *Server) createSetAnalogOutputTool() protocol.Tool {
 			},
 			Required: []string{"pin", "value"},
 		},
+		Annotations: protocol.MutatingAnnotations("Set Analog Output", true, true, false),
 	}
 }
 
@@ -146,6 +151,19 @@ func (s *Server) createGetAnalogOutputTool() protocol.Tool {
 			},
 			Required: []string{"pin"},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("Read Analog Output", false),
+	}
+}
+
+func (s *Server) createGetAnalogValuesTool() protocol.Tool {
+	return protocol.Tool{
+		Name:        "get_analog_values",
+		Description: "Read the voltage of every analog input and output (0-3) at once, along with each value converted to its real-world units (°C, g, %, etc.)",
+		InputSchema: protocol.InputSchema{
+			Type:       "object",
+			Properties: map[string]protocol.ToolProperty{},
+		},
+		Annotations: protocol.ReadOnlyAnnotations("Read Analog Values", false),
 	}
 }
 
@@ -157,6 +175,7 @@ func (s *Server) createGetSystemStatusTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("System Status", false),
 	}
 }
 
@@ -181,7 +200,7 @@ func (s *Server) handleGetDigitalInput(params interface{}) (interface{}, error)
 		// Use direct I/O bank mode
 		value, err = s.ioBank.GetDigitalInput(pin)
 	}
-	
+
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +232,7 @@ func (s *Server) handleSetDigitalOutput(params interface{}) (interface{}, error)
 		// Use direct I/O bank mode
 		err = s.ioBank.SetDigitalOutput(pin, value)
 	}
-	
+
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +265,7 @@ func (s *Server) handleUnsetDigitalOutput(params interface{}) (interface{}, erro
 		// Use direct I/O bank mode
 		err = s.ioBank.SetDigitalOutput(pin, value)
 	}
-	
+
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +298,7 @@ func (s *Server) handleGetDigitalOutput(params interface{}) (interface{}, error)
 	} else {
 		return nil, fmt.Errorf("no IOBank or HTTP client available")
 	}
-	
+
 	if err != nil {
 		return nil, err
 	}
@@ -311,7 +330,7 @@ func (s *Server) handleGetAnalogInput(params interface{}) (interface{}, error) {
 	} else {
 		return nil, fmt.Errorf("no IOBank or HTTP client available")
 	}
-	
+
 	if err != nil {
 		return nil, err
 	}
@@ -323,8 +342,6 @@ func (s *Server) handleGetAnalogInput(params interface{}) (interface{}, error) {
 	}, nil
 }
 
-
-
 func (s *Server) handleSetAnalogOutput(params interface{}) (interface{}, error) {
 	pin, err := s.extractIntParam(params, "pin")
 	if err != nil {
@@ -340,6 +357,9 @@ func (s *Server) handleSetAnalogOutput(params interface{}) (interface{}, error)
 	if pin < 0 || pin > 3 {
 		return nil, fmt.Errorf("analog output pin %d out of range (0-3)", pin)
 	}
+	if value < 0 || value > config.AnalogFullScaleVolts {
+		return nil, fmt.Errorf("analog output value %.3f out of range (0.0-%.1fV)", value, config.AnalogFullScaleVolts)
+	}
 
 	if s.httpClient != nil {
 		// Use HTTP client mode
@@ -350,7 +370,7 @@ func (s *Server) handleSetAnalogOutput(params interface{}) (interface{}, error)
 	} else {
 		return nil, fmt.Errorf("no IOBank or HTTP client available")
 	}
-	
+
 	if err != nil {
 		return nil, err
 	}
@@ -384,7 +404,7 @@ func (s *Server) handleGetAnalogOutput(params interface{}) (interface{}, error)
 	} else {
 		return nil, fmt.Errorf("no IOBank or HTTP client available")
 	}
-	
+
 	if err != nil {
 		return nil, err
 	}
@@ -396,9 +416,24 @@ func (s *Server) handleGetAnalogOutput(params interface{}) (interface{}, error)
 	}, nil
 }
 
+// handleGetAnalogValues returns the analog part of the system status
+func (s *Server) handleGetAnalogValues(params interface{}) (interface{}, error) {
+	status, err := s.handleGetSystemStatus(nil)
+	if err != nil {
+		return nil, err
+	}
+	statusMap := status.(map[string]interface{})
+
+	values := map[string]interface{}{"unit": "V"}
+	for _, key := range []string{"analog_inputs", "analog_outputs", "analog_inputs_engineering", "analog_outputs_engineering"} {
+		values[key] = statusMap[key]
+	}
+	return values, nil
+}
+
 func (s *Server) handleGetSystemStatus(params interface{}) (interface{}, error) {
 	var status map[string]interface{}
-	
+
 	if s.ioBank != nil {
 		// Direct mode - use IOBank directly
 		status = s.ioBank.GetStatus()
@@ -409,24 +444,14 @@ func (s *Server) handleGetSystemStatus(params interface{}) (interface{}, error)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get system status via HTTP: %v", err)
 		}
+		status["connection"] = s.httpClient.Health()
 	} else {
 		return nil, fmt.Errorf("no IOBank or HTTP client available")
 	}
-	
-	// Add labels to the status (import the function from api package)
-	labels := config.GetIOLabels()
-	status["labels"] = map[string]interface{}{
-		"digital_inputs":  labels.DigitalInputs,
-		"digital_outputs": labels.DigitalOutputs,
-		"analog_inputs":   labels.AnalogInputs,
-		"analog_outputs":  labels.AnalogOutputs,
-	}
-	
-	status["analog_ranges"] = map[string]interface{}{
-		"inputs":  labels.AnalogInputRanges,
-		"outputs": labels.AnalogOutputRanges,
-	}
-	
+
+	// Add the schema version, labels and engineering units as the HTTP API does
+	status = api.AddLabelsToStatus(status)
+
 	return status, nil
 }
 
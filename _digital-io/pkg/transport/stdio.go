@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/richard-senior/mcp/_digital-io/internal/logger"
 	"github.com/richard-senior/mcp/_digital-io/pkg/protocol"
@@ -19,6 +20,8 @@ const prettyPrint = true
 type StdioTransport struct {
 	reader *bufio.Reader
 	writer *bufio.Writer
+	// writeMu stops notifications sent from the simulation interleaving with responses
+	writeMu sync.Mutex
 }
 
 // NewStdioTransport creates a new transport that uses stdin/stdout
@@ -104,6 +107,20 @@ func (t *StdioTransport) ReadRequest() (*protocol.JsonRpcRequest, error) {
 
 // WriteResponse writes a JSON-RPC response to stdout
 func (t *StdioTransport) WriteResponse(response *protocol.JsonRpcResponse) error {
+	if err := t.writeMessage(response); err != nil {
+		return err
+	}
+	logger.Info("Response sent successfully")
+	return nil
+}
+
+// WriteNotification writes a JSON-RPC notification to stdout
+func (t *StdioTransport) WriteNotification(notification *protocol.JsonRpcRequest) error {
+	return t.writeMessage(notification)
+}
+
+// writeMessage writes a single JSON-RPC message to stdout followed by a newline
+func (t *StdioTransport) writeMessage(response any) error {
 	var responseBytes []byte
 	var err error
 
@@ -136,6 +153,9 @@ func (t *StdioTransport) WriteResponse(response *protocol.JsonRpcResponse) error
 
 	logger.Debug("Sending response:", string(responseBytes))
 
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
 	// Write the response to stdout
 	if _, err := t.writer.Write(responseBytes); err != nil {
 		logger.Error("Failed to write response:", err)
@@ -147,7 +167,5 @@ func (t *StdioTransport) WriteResponse(response *protocol.JsonRpcResponse) error
 		logger.Error("Failed to flush response:", err)
 		return err
 	}
-
-	logger.Info("Response sent successfully")
 	return nil
 }
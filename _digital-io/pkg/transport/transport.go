@@ -8,4 +8,5 @@ import (
 type Transport interface {
 	ReadRequest() (*protocol.JsonRpcRequest, error)
 	WriteResponse(*protocol.JsonRpcResponse) error
+	WriteNotification(*protocol.JsonRpcRequest) error
 }
@@ -0,0 +1,260 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/_digital-io/internal/api"
+	"github.com/richard-senior/mcp/_digital-io/internal/config"
+	"github.com/richard-senior/mcp/_digital-io/internal/iobank"
+)
+
+func newTestRouter(cfg config.HTTPConfig) http.Handler {
+	return api.NewAPIHandlerWithConfig(iobank.NewIOBank(), cfg).SetupRoutes()
+}
+
+func TestCORSSameOriginByDefault(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Origin header, got '%s'", got)
+	}
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{
+		CORS: config.CORSConfig{
+			AllowedOrigins: []string{"http://localhost:3000"},
+			AllowedMethods: []string{"GET", "POST"},
+			AllowedHeaders: []string{"Content-Type"},
+		},
+	})
+
+	// Preflight
+	req := httptest.NewRequest(http.MethodOptions, "/digital/output/3", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected preflight status 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:3000" {
+		t.Errorf("Expected allowed origin header, got '%s'", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+		t.Errorf("Expected allowed methods 'GET, POST', got '%s'", got)
+	}
+
+	// Actual request from an origin that is not allowed
+	req = httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Origin", "http://evil.example.com")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no CORS header for disallowed origin, got '%s'", got)
+	}
+}
+
+func TestAccessLogRequestID(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{
+		AccessLog: config.AccessLogConfig{
+			Enabled:        true,
+			RedactedFields: []string{"Authorization", "token"},
+		},
+	})
+
+	// The handler must still see the body after it has been logged
+	body := strings.NewReader(`{"value": true, "token": "secret"}`)
+	req := httptest.NewRequest(http.MethodPost, "/digital/output/3", body)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get(api.RequestIDHeader) == "" {
+		t.Error("Expected a request ID header on the response")
+	}
+
+	// An incoming request ID is propagated
+	req = httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set(api.RequestIDHeader, "abc123")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if got := rec.Header().Get(api.RequestIDHeader); got != "abc123" {
+		t.Errorf("Expected request ID 'abc123', got '%s'", got)
+	}
+}
+
+func TestAccessLogDisabled(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if got := rec.Header().Get(api.RequestIDHeader); got != "" {
+		t.Errorf("Expected no request ID when the access log is disabled, got '%s'", got)
+	}
+}
+
+func TestBearerTokenAuth(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{APIToken: "s3cret"})
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		expected      int
+	}{
+		{"missing token", "/digital/input/1", "", http.StatusUnauthorized},
+		{"wrong token", "/digital/input/1", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", "/digital/input/1", "Basic s3cret", http.StatusUnauthorized},
+		{"valid token", "/digital/input/1", "Bearer s3cret", http.StatusOK},
+		{"health exempt", "/status", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.expected, rec.Code)
+		}
+	}
+}
+
+func TestStatusSchema(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var status map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if status["schemaVersion"] != float64(api.StatusSchemaVersion) {
+		t.Errorf("Expected schemaVersion %d, got %v", api.StatusSchemaVersion, status["schemaVersion"])
+	}
+	for _, field := range []string{"digital_inputs", "analog_inputs", "simulation_running", "labels", "analog_ranges"} {
+		if _, ok := status[field]; !ok {
+			t.Errorf("Expected existing field '%s' to be kept", field)
+		}
+	}
+	readings, ok := status["analog_inputs_engineering"].([]interface{})
+	if !ok || len(readings) != 4 {
+		t.Fatalf("Expected 4 analog input readings, got %v", status["analog_inputs_engineering"])
+	}
+	kettle := readings[1].(map[string]interface{})
+	if kettle["pin"] != float64(1) || kettle["raw"] != 1.0 || kettle["raw_unit"] != "V" {
+		t.Errorf("Unexpected kettle temperature reading %v", kettle)
+	}
+}
+
+func TestAnalogRangeEngineeringValue(t *testing.T) {
+	kettle := config.AnalogRange{MinValue: "0", MaxValue: "100", Unit: "°C"}
+	if value, ok := kettle.EngineeringValue(1.0); !ok || value != 20 {
+		t.Errorf("Expected 1V to be 20°C, got %v", value)
+	}
+	offset := config.AnalogRange{MinValue: "-50", MaxValue: "50", Unit: "°C"}
+	if value, ok := offset.EngineeringValue(2.5); !ok || value != 0 {
+		t.Errorf("Expected 2.5V to be 0°C, got %v", value)
+	}
+	if _, ok := (config.AnalogRange{MinValue: "low", MaxValue: "5"}).EngineeringValue(1); ok {
+		t.Error("Expected a non-numeric range to fail conversion")
+	}
+}
+
+func TestRateLimitWrites(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{
+		RateLimit: config.RateLimitConfig{WriteRate: 1, WriteBurst: 3},
+	})
+
+	post := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/digital/output/3", strings.NewReader(`{"value": true}`))
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		if rec := post("192.0.2.1:1234"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected request %d within the burst to succeed, got %d", i+1, rec.Code)
+		}
+	}
+	rec := post("192.0.2.1:1234")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 once the burst is used, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Expected Retry-After of 1 second, got '%s'", got)
+	}
+
+	// other clients have their own allowance
+	if rec := post("192.0.2.2:1234"); rec.Code != http.StatusOK {
+		t.Errorf("Expected a different client to be allowed, got %d", rec.Code)
+	}
+
+	// reads aren't limited by the write limit
+	for i := 0; i < 20; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status reads to be unaffected by the write limit, got %d", rec.Code)
+		}
+	}
+}
+
+func TestRateLimitPerRoute(t *testing.T) {
+	router := newTestRouter(config.HTTPConfig{
+		RateLimit: config.RateLimitConfig{WriteRate: 1, WriteBurst: 1, ReadRate: 100, PerRoute: true},
+	})
+
+	codes := []int{}
+	for _, addr := range []string{"192.0.2.1:1234", "192.0.2.2:1234"} {
+		req := httptest.NewRequest(http.MethodPost, "/digital/output/4", strings.NewReader(`{"value": true}`))
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("Expected clients to share the route's bucket, got %v", codes)
+	}
+}
+
+func TestInterlockConflict(t *testing.T) {
+	router := api.NewAPIHandlerWithConfig(iobank.NewIOBankWithConfig(iobank.Config{SafeMode: true}), config.HTTPConfig{}).SetupRoutes()
+
+	req := httptest.NewRequest(http.MethodPost, "/digital/output/3", strings.NewReader(`{"value": true}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409 for an interlocked output, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "kettle is empty") {
+		t.Errorf("Expected the reason in the response, got '%s'", rec.Body.String())
+	}
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/richard-senior/mcp/_digital-io/pkg/server"
+)
+
+func TestHealthCheckRetriesUnreachableServer(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+
+	var retries []int
+	backoff := func(retry int, baseDelay time.Duration) time.Duration {
+		retries = append(retries, retry)
+		return baseDelay
+	}
+	client := server.NewHTTPClient(url, server.WithMaxRetries(3), server.WithBaseDelay(time.Millisecond), server.WithBackoff(backoff))
+
+	err := client.HealthCheck()
+	var unreachable *server.ServerUnreachableError
+	if !errors.As(err, &unreachable) {
+		t.Fatalf("Expected a ServerUnreachableError, got %v", err)
+	}
+	if len(retries) != 3 || retries[0] != 1 || retries[2] != 3 {
+		t.Errorf("Expected 3 retries numbered from 1, got %v", retries)
+	}
+}
+
+func TestHealthCheckDoesNotRetryServerError(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	client := server.NewHTTPClient(ts.URL, server.WithBaseDelay(time.Millisecond))
+	err := client.HealthCheck()
+	var responseErr *server.ServerResponseError
+	if !errors.As(err, &responseErr) {
+		t.Fatalf("Expected a ServerResponseError, got %v", err)
+	}
+	if responseErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", responseErr.StatusCode)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single request, got %d", calls)
+	}
+}
+
+func TestClientReconnectsAfterServerRestart(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"digital_outputs":[]}`))
+	}))
+	addr := ts.Listener.Addr().String()
+
+	var restarted *httptest.Server
+	defer func() {
+		if restarted != nil {
+			restarted.Close()
+		}
+	}()
+	// the server comes back on the same address while the client is backing off
+	backoff := func(retry int, baseDelay time.Duration) time.Duration {
+		if restarted == nil {
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				t.Fatalf("Failed to restart the server: %v", err)
+			}
+			restarted = httptest.NewUnstartedServer(ts.Config.Handler)
+			restarted.Listener = listener
+			restarted.Start()
+		}
+		return baseDelay
+	}
+	client := server.NewHTTPClient(ts.URL, server.WithBaseDelay(time.Millisecond), server.WithBackoff(backoff))
+
+	if _, err := client.GetSystemStatus(); err != nil {
+		t.Fatalf("Expected the first request to succeed, got %v", err)
+	}
+	ts.Close()
+
+	if _, err := client.GetSystemStatus(); err != nil {
+		t.Fatalf("Expected the client to reconnect to the restarted server, got %v", err)
+	}
+	if restarted == nil {
+		t.Fatal("Expected the request to be retried")
+	}
+	if health := client.Health(); !health.Connected || health.ConsecutiveFailures != 0 {
+		t.Errorf("Expected the client to be connected, got %+v", health)
+	}
+}
+
+func TestClientHealthReportsFailures(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+
+	client := server.NewHTTPClient(url, server.WithMaxRetries(1), server.WithBaseDelay(time.Millisecond))
+	if health := client.Health(); !health.Connected {
+		t.Errorf("Expected a new client to assume it is connected, got %+v", health)
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.SetDigitalOutput(1, true); err == nil {
+			t.Fatal("Expected an error with the server down")
+		}
+	}
+	health := client.Health()
+	if health.Connected || health.ConsecutiveFailures != 2 || health.LastError == "" {
+		t.Errorf("Expected two consecutive failures to be reported, got %+v", health)
+	}
+}
+
+func TestBackoffStrategies(t *testing.T) {
+	base := 100 * time.Millisecond
+	if got := server.ConstantBackoff(3, base); got != base {
+		t.Errorf("Expected constant backoff of %v, got %v", base, got)
+	}
+	if got := server.LinearBackoff(3, base); got != 300*time.Millisecond {
+		t.Errorf("Expected linear backoff of 300ms, got %v", got)
+	}
+	if got := server.ExponentialBackoff(3, base); got != 400*time.Millisecond {
+		t.Errorf("Expected exponential backoff of 400ms, got %v", got)
+	}
+}
@@ -1,6 +1,8 @@
 package test
 
 import (
+	"errors"
+	"math"
 	"testing"
 	"time"
 
@@ -212,3 +214,109 @@ func TestGetAllMethods(t *testing.T) {
 		t.Errorf("Expected 8 analog outputs, got %d", len(analogOutputs))
 	}
 }
+
+// kettleTemperatureAfter heats the kettle for the given simulated duration using the given tick
+func kettleTemperatureAfter(t *testing.T, tick time.Duration, duration time.Duration) float64 {
+	t.Helper()
+	bank := iobank.NewIOBankWithConfig(iobank.Config{TickInterval: tick})
+	if err := bank.SetDigitalOutput(3, true); err != nil {
+		t.Fatalf("Failed to switch on the kettle: %v", err)
+	}
+	for i := 0; i < int(duration/tick); i++ {
+		bank.Step()
+	}
+	volts, err := bank.GetAnalogInput(1)
+	if err != nil {
+		t.Fatalf("Failed to read the kettle temperature: %v", err)
+	}
+	return volts * 20.0
+}
+
+func TestTickIntervalKeepsPhysicsRates(t *testing.T) {
+	slow := kettleTemperatureAfter(t, 500*time.Millisecond, 10*time.Second)
+	fast := kettleTemperatureAfter(t, 100*time.Millisecond, 10*time.Second)
+
+	// 10 seconds at 100°C/min from 20°C
+	expected := 20.0 + 100.0*10.0/60.0
+	if math.Abs(slow-expected) > 1e-6 {
+		t.Errorf("Expected %.3f°C with a 500ms tick, got %.3f°C", expected, slow)
+	}
+	if math.Abs(fast-slow) > 1e-6 {
+		t.Errorf("Expected a 100ms tick to reach the same temperature as a 500ms tick, got %.3f°C and %.3f°C", fast, slow)
+	}
+}
+
+func TestHeaterPowerSetsHeatingRate(t *testing.T) {
+	bank := iobank.NewIOBank()
+	if power, _ := bank.GetAnalogOutput(iobank.HeaterPowerOutput); power != iobank.FullHeaterPowerVolts {
+		t.Fatalf("Expected the heater to start at full power, got %.2fV", power)
+	}
+	if err := bank.SetAnalogOutput(iobank.HeaterPowerOutput, iobank.FullHeaterPowerVolts/2); err != nil {
+		t.Fatal(err)
+	}
+	if err := bank.SetDigitalOutput(3, true); err != nil {
+		t.Fatal(err)
+	}
+	// 12 seconds at half of 100°C/min from 20°C
+	for i := 0; i < 24; i++ {
+		bank.Step()
+	}
+	volts, _ := bank.GetAnalogInput(1)
+	if math.Abs(volts*20.0-30.0) > 1e-6 {
+		t.Errorf("Expected half power to heat the kettle to 30°C, got %.3f°C", volts*20.0)
+	}
+
+	bank.Reset()
+	if power, _ := bank.GetAnalogOutput(iobank.HeaterPowerOutput); power != iobank.FullHeaterPowerVolts {
+		t.Errorf("Expected a reset to restore full heater power, got %.2fV", power)
+	}
+}
+
+func TestSafeModeInterlocks(t *testing.T) {
+	bank := iobank.NewIOBankWithConfig(iobank.Config{SafeMode: true})
+
+	// the kettle starts empty
+	err := bank.SetDigitalOutput(3, true)
+	if !errors.Is(err, iobank.ErrInterlock) {
+		t.Fatalf("Expected heating an empty kettle to be refused, got %v", err)
+	}
+	if on, _ := bank.GetDigitalOutput(3); on {
+		t.Error("Expected the heater to stay off when refused")
+	}
+	if err := bank.SetDigitalOutput(2, true); !errors.Is(err, iobank.ErrInterlock) {
+		t.Errorf("Expected pouring from an empty kettle to be refused, got %v", err)
+	}
+	if err := bank.SetDigitalOutput(3, false); err != nil {
+		t.Errorf("Expected switching an output off to always be allowed, got %v", err)
+	}
+
+	// fill the kettle for 5 seconds then the heater may be used
+	if err := bank.SetDigitalOutput(1, true); err != nil {
+		t.Fatalf("Failed to open the inlet valve: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		bank.Step()
+	}
+	if err := bank.SetDigitalOutput(3, true); err != nil {
+		t.Errorf("Expected heating a filled kettle to be allowed, got %v", err)
+	}
+	// but pouring still needs a cup
+	if err := bank.SetDigitalOutput(2, true); !errors.Is(err, iobank.ErrInterlock) {
+		t.Errorf("Expected pouring with no cup to be refused, got %v", err)
+	}
+}
+
+func TestPermissiveByDefault(t *testing.T) {
+	bank := iobank.NewIOBank()
+	if bank.SafeMode() {
+		t.Fatal("Expected safe mode to be off by default")
+	}
+	if err := bank.SetDigitalOutput(3, true); err != nil {
+		t.Errorf("Expected heating an empty kettle to be allowed outside safe mode, got %v", err)
+	}
+
+	bank.SetSafeMode(true)
+	if err := bank.SetDigitalOutput(2, true); !errors.Is(err, iobank.ErrInterlock) {
+		t.Errorf("Expected interlocks once safe mode is enabled, got %v", err)
+	}
+}
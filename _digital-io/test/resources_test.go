@@ -0,0 +1,114 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/richard-senior/mcp/_digital-io/internal/iobank"
+	"github.com/richard-senior/mcp/_digital-io/pkg/protocol"
+	"github.com/richard-senior/mcp/_digital-io/pkg/server"
+)
+
+// scriptedTransport feeds the server a fixed list of requests and records what it writes
+type scriptedTransport struct {
+	requests []*protocol.JsonRpcRequest
+
+	mu            sync.Mutex
+	responses     []*protocol.JsonRpcResponse
+	notifications []*protocol.JsonRpcRequest
+	// order records "response" or the notification method as each message is written
+	order []string
+}
+
+func (t *scriptedTransport) ReadRequest() (*protocol.JsonRpcRequest, error) {
+	if len(t.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := t.requests[0]
+	t.requests = t.requests[1:]
+	return req, nil
+}
+
+func (t *scriptedTransport) WriteResponse(resp *protocol.JsonRpcResponse) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.responses = append(t.responses, resp)
+	t.order = append(t.order, "response")
+	return nil
+}
+
+func (t *scriptedTransport) WriteNotification(n *protocol.JsonRpcRequest) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notifications = append(t.notifications, n)
+	t.order = append(t.order, n.Method)
+	return nil
+}
+
+func mustRequest(t *testing.T, method protocol.MethodType, params any, id int) *protocol.JsonRpcRequest {
+	t.Helper()
+	req, err := protocol.NewJsonRpcRequest(string(method), params, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestBankStateResource(t *testing.T) {
+	uri := protocol.ResourceParams{URI: server.BankStateURI}
+	transport := &scriptedTransport{requests: []*protocol.JsonRpcRequest{
+		mustRequest(t, protocol.MethodResourcesList, nil, 1),
+		// changes before subscribing aren't notified
+		mustRequest(t, protocol.MethodToolsCall, map[string]any{"name": "set_digital_output", "arguments": map[string]any{"pin": 9}}, 2),
+		mustRequest(t, protocol.MethodResourcesSubscribe, uri, 3),
+		mustRequest(t, protocol.MethodToolsCall, map[string]any{"name": "set_digital_output", "arguments": map[string]any{"pin": 1}}, 4),
+		mustRequest(t, protocol.MethodResourcesRead, uri, 5),
+		mustRequest(t, protocol.MethodResourcesUnsubscribe, uri, 6),
+		mustRequest(t, protocol.MethodToolsCall, map[string]any{"name": "unset_digital_output", "arguments": map[string]any{"pin": 1}}, 7),
+	}}
+
+	bank := iobank.NewIOBank()
+	if err := server.NewServer(transport, bank).Start(); err != io.EOF {
+		t.Fatalf("Expected the server to stop at the end of the requests, got %v", err)
+	}
+
+	expected := []string{"response", "response", "response", string(protocol.MethodResourcesUpdated), "response", "response", "response", "response"}
+	if strings.Join(transport.order, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected messages %v, got %v", expected, transport.order)
+	}
+	for _, resp := range transport.responses {
+		if resp.Error != nil {
+			t.Fatalf("Unexpected error response %v", resp.Error)
+		}
+	}
+
+	var params protocol.ResourceParams
+	if err := json.Unmarshal(transport.notifications[0].Params, &params); err != nil || params.URI != server.BankStateURI {
+		t.Errorf("Expected the notification to name %s, got %s", server.BankStateURI, transport.notifications[0].Params)
+	}
+
+	var list struct {
+		Resources []protocol.Resource `json:"resources"`
+	}
+	if err := json.Unmarshal(transport.responses[0].Result, &list); err != nil || len(list.Resources) != 1 || list.Resources[0].URI != server.BankStateURI {
+		t.Errorf("Expected resources/list to return the bank state, got %s", transport.responses[0].Result)
+	}
+
+	var read struct {
+		Contents []protocol.ResourceContents `json:"contents"`
+	}
+	if err := json.Unmarshal(transport.responses[4].Result, &read); err != nil || len(read.Contents) != 1 {
+		t.Fatalf("Expected a single resource content, got %s", transport.responses[4].Result)
+	}
+	var state map[string]interface{}
+	if err := json.Unmarshal([]byte(read.Contents[0].Text), &state); err != nil {
+		t.Fatalf("Expected the resource to be JSON: %v", err)
+	}
+	outputs, _ := state["digital_outputs"].([]interface{})
+	if len(outputs) != 16 || outputs[1] != true {
+		t.Errorf("Expected the state to show digital output 1 on, got %v", state["digital_outputs"])
+	}
+}
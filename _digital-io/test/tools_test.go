@@ -0,0 +1,61 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/_digital-io/internal/iobank"
+	"github.com/richard-senior/mcp/_digital-io/pkg/protocol"
+	"github.com/richard-senior/mcp/_digital-io/pkg/server"
+)
+
+func TestAnalogTools(t *testing.T) {
+	call := func(name string, args map[string]any, id int) *protocol.JsonRpcRequest {
+		return mustRequest(t, protocol.MethodToolsCall, map[string]any{"name": name, "arguments": args}, id)
+	}
+	transport := &scriptedTransport{requests: []*protocol.JsonRpcRequest{
+		call("set_analog_output", map[string]any{"pin": 2, "value": 5.5}, 1),
+		call("set_analog_output", map[string]any{"pin": 2, "value": 1.25}, 2),
+		call("get_analog_values", map[string]any{}, 3),
+	}}
+
+	bank := iobank.NewIOBank()
+	if err := server.NewServer(transport, bank).Start(); err != io.EOF {
+		t.Fatalf("Expected the server to stop at the end of the requests, got %v", err)
+	}
+	if len(transport.responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(transport.responses))
+	}
+
+	if resp := transport.responses[0]; resp.Error == nil || !strings.Contains(resp.Error.Message, "out of range") {
+		t.Errorf("Expected a value above 5V to be rejected, got %s", resp.Result)
+	}
+	if value, _ := bank.GetAnalogOutput(2); value != 1.25 {
+		t.Errorf("Expected analog output 2 to be set to 1.25V, got %.3fV", value)
+	}
+
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(transport.responses[2].Result, &result); err != nil || len(result.Content) != 1 {
+		t.Fatalf("Unexpected get_analog_values result %s", transport.responses[2].Result)
+	}
+	var values struct {
+		AnalogInputs  []float64 `json:"analog_inputs"`
+		AnalogOutputs []float64 `json:"analog_outputs"`
+		Engineering   []any     `json:"analog_outputs_engineering"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &values); err != nil {
+		t.Fatalf("Expected the analog values as JSON: %v", err)
+	}
+	if len(values.AnalogInputs) != 4 || len(values.AnalogOutputs) != 4 || len(values.Engineering) != 4 {
+		t.Fatalf("Expected 4 of each analog value, got %+v", values)
+	}
+	if values.AnalogOutputs[2] != 1.25 || values.AnalogOutputs[iobank.HeaterPowerOutput] != iobank.FullHeaterPowerVolts {
+		t.Errorf("Unexpected analog outputs %v", values.AnalogOutputs)
+	}
+}
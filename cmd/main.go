@@ -1,8 +1,6 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
@@ -11,6 +9,7 @@ import (
 	"github.com/richard-senior/mcp/internal/logger"
 	"github.com/richard-senior/mcp/pkg/server"
 	"github.com/richard-senior/mcp/pkg/tools"
+	"github.com/richard-senior/mcp/pkg/transport"
 )
 
 func main() {
@@ -22,11 +21,19 @@ func main() {
 	// Set correct GOARCH before any tool operations
 	setCorrectArchitecture()
 
+	// The selftest subcommand checks connectivity to the external services then exits
+	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+		os.Exit(selfTest())
+	}
+
 	// Disable logging for MCP server mode to avoid interfering with JSON-RPC
 	logger.SetLevel(logger.FATAL)
 
+	// Keep the real stdout for JSON-RPC only, anything else printed goes to stderr
+	stdout := transport.RedirectStdout()
+
 	// Initialize the MCP server singleton
-	s := server.GetInstance()
+	s := server.InitInstance(transport.NewStdioTransportWithIO(os.Stdin, stdout))
 
 	//logger.Info("Starting github.com/richard-senior/mcp application")
 
@@ -46,20 +53,15 @@ func main() {
 	*/
 }
 
-func handleGoogleSearch(query string) {
-	params := map[string]any{
-		"query": query,
-		"num":   5,
+// selfTest runs the connectivity checks, printing a report to stdout
+// Returns the process exit code, non zero if any required dependency is unreachable
+func selfTest() int {
+	results := tools.RunSelfTest(tools.DefaultSelfTestChecks())
+	tools.WriteSelfTestReport(os.Stdout, results)
+	if !tools.SelfTestPassed(results) {
+		return 1
 	}
-
-	result, err := tools.HandleGoogleSearchTool(params)
-	if err != nil {
-		logger.Error("Search failed:", err)
-		os.Exit(1)
-	}
-
-	output, _ := json.MarshalIndent(result, "", "  ")
-	fmt.Println(string(output))
+	return 0
 }
 
 func setCorrectArchitecture() {
@@ -6,7 +6,11 @@ require (
 	github.com/JohannesKaufmann/html-to-markdown/v2 v2.3.3
 	github.com/PuerkitoBio/goquery v1.10.3
 	github.com/andybalholm/brotli v1.1.1
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/go-delve/delve v1.25.2
+	golang.org/x/image v0.26.0
+	golang.org/x/net v0.39.0
+	golang.org/x/text v0.24.0
 	modernc.org/sqlite v1.38.0
 )
 
@@ -23,7 +27,6 @@ require (
 	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
 	golang.org/x/arch v0.11.0 // indirect
 	golang.org/x/exp v0.0.0-20250408133849-7e4ce0ab07d0 // indirect
-	golang.org/x/net v0.39.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/telemetry v0.0.0-20241106142447-58a1122356f5 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
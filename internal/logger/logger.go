@@ -70,6 +70,11 @@ func SetLevel(level LogLevel) {
 	defaultLogger.level = level
 }
 
+// GetLevel returns the current level of the default logger
+func GetLevel() LogLevel {
+	return defaultLogger.level
+}
+
 func SetShowDateTime(value bool) {
 	showDateTime = value
 	updateLoggerFlags(defaultLogger)
@@ -1,11 +1,27 @@
 package debugger
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/go-delve/delve/service/api"
 )
 
+// DelveError wraps an error returned by Delve so that it can be told apart from
+// errors in the arguments given to the debugger
+type DelveError struct {
+	Op  string // The Delve operation which failed
+	Err error
+}
+
+func (e *DelveError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.Op, e.Err)
+}
+
+func (e *DelveError) Unwrap() error {
+	return e.Err
+}
+
 // DebugContext provides shared context across all debug responses
 type DebugContext struct {
 	DelveState      *api.DebuggerState `json:"-"`                         // Internal Delve state
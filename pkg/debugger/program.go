@@ -601,8 +601,10 @@ func (c *Client) Close() (*CloseResponse, error) {
 	// Wait for completion or timeout
 	var detachErr error
 	select {
-	case detachErr = <-errChan:
-		// Operation completed successfully
+	case err := <-errChan:
+		if err != nil {
+			detachErr = &DelveError{Op: "detach", Err: err}
+		}
 	case <-ctx.Done():
 		logger.Debug("Warning: Detach operation timed out after 5 seconds")
 		detachErr = ctx.Err()
@@ -702,7 +704,7 @@ func (c *Client) DebugSourceFile(sourceFile string, args []string) DebugSourceRe
 	response := c.LaunchProgram(debugBinary, args)
 	if response.Context.ErrorMessage != "" {
 		gobuild.Remove(debugBinary)
-		return c.createDebugSourceResponse(nil, sourceFile, debugBinary, args, fmt.Errorf(response.Context.ErrorMessage))
+		return c.createDebugSourceResponse(nil, sourceFile, debugBinary, args, fmt.Errorf("%s", response.Context.ErrorMessage))
 	}
 
 	// Store the binary path for cleanup
@@ -794,7 +796,7 @@ func (c *Client) DebugTest(testFilePath string, testName string, testFlags []str
 	response2 := c.LaunchProgram(debugBinary, args)
 	if response2.Context.ErrorMessage != "" {
 		gobuild.Remove(debugBinary)
-		return c.createDebugTestResponse(nil, &response, fmt.Errorf(response.Context.ErrorMessage))
+		return c.createDebugTestResponse(nil, &response, fmt.Errorf("%s", response.Context.ErrorMessage))
 	}
 
 	// Store the binary path for cleanup
@@ -26,22 +26,30 @@ func NewPromptRegistry() *PromptRegistry {
 		homeDir = "."
 	}
 
-	baseDir := filepath.Join(homeDir, ".mcp", "prompts")
+	registry := NewPromptRegistryWithDir(filepath.Join(homeDir, ".mcp", "prompts"))
 
+	// Create sample prompts if directory is empty
+	registry.ensureSamplePrompts()
+
+	return registry
+}
+
+// NewPromptRegistryWithDir creates a prompt registry storing prompts in the given directory
+func NewPromptRegistryWithDir(baseDir string) *PromptRegistry {
 	// Create the directory if it doesn't exist
-	err = os.MkdirAll(baseDir, 0755)
+	err := os.MkdirAll(baseDir, 0755)
 	if err != nil {
 		logger.Error("Failed to create prompt registry directory", err)
 	}
 
-	registry := &PromptRegistry{
+	return &PromptRegistry{
 		baseDir: baseDir,
 	}
+}
 
-	// Create sample prompts if directory is empty
-	registry.ensureSamplePrompts()
-
-	return registry
+// BaseDir returns the directory prompts are stored in
+func (pr *PromptRegistry) BaseDir() string {
+	return pr.baseDir
 }
 
 // GetPromptPath returns the file path for a prompt ID
@@ -75,6 +83,11 @@ func (pr *PromptRegistry) GetPrompt(id string) (*protocol.Prompt, error) {
 		return nil, fmt.Errorf("failed to parse prompt file: %w", err)
 	}
 
+	// Reject malformed prompts now rather than leaving braces in the rendered text later
+	if err := ValidatePrompt(prompt); err != nil {
+		return nil, err
+	}
+
 	return &prompt, nil
 }
 
@@ -243,16 +256,27 @@ func (pr *PromptRegistry) ensureSamplePrompts() {
 	}
 
 	for _, prompt := range samplePrompts {
-		// Check if prompt already exists
-		_, err := pr.GetPrompt(prompt.ID)
+		path, err := pr.GetPromptPath(prompt.ID)
 		if err != nil {
-			// Prompt doesn't exist, create it
-			err := pr.SavePrompt(prompt)
-			if err != nil {
-				logger.Warn("Failed to create sample prompt", prompt.ID, err)
-			} else {
-				logger.Info("Created sample prompt", prompt.ID)
+			logger.Warn("Invalid sample prompt", prompt.ID, err)
+			continue
+		}
+		// Never overwrite an existing prompt, the user may have edited it
+		if _, err := os.Stat(path); err == nil {
+			if _, err := pr.GetPrompt(prompt.ID); err != nil {
+				logger.Warn("Existing prompt", prompt.ID, "is invalid and won't be served", err)
 			}
+			continue
+		} else if !os.IsNotExist(err) {
+			logger.Warn("Failed to check for sample prompt", prompt.ID, err)
+			continue
+		}
+
+		// Prompt doesn't exist, create it
+		if err := pr.SavePrompt(prompt); err != nil {
+			logger.Warn("Failed to create sample prompt", prompt.ID, err)
+		} else {
+			logger.Info("Created sample prompt", prompt.ID)
 		}
 	}
 }
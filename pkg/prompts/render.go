@@ -0,0 +1,33 @@
+package prompts
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// unsubstitutedPattern finds placeholders left in rendered content
+var unsubstitutedPattern = regexp.MustCompile(`\{\{([A-Za-z0-9_-]+)\}\}`)
+
+// RenderPrompt substitutes the given arguments into the prompt's {{variable}} placeholders.
+// Arguments don't all have to be supplied; the names of any placeholders left in the
+// content are returned sorted and without duplicates
+func RenderPrompt(p protocol.Prompt, args map[string]string) (string, []string) {
+	content := p.Content
+	for key, value := range args {
+		content = strings.ReplaceAll(content, "{{"+key+"}}", value)
+	}
+
+	seen := map[string]bool{}
+	unsubstituted := []string{}
+	for _, match := range unsubstitutedPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			unsubstituted = append(unsubstituted, match[1])
+		}
+	}
+	sort.Strings(unsubstituted)
+	return content, unsubstituted
+}
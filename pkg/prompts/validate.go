@@ -0,0 +1,67 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// Errors returned by ValidatePrompt, wrapped with the details of where the problem is
+var (
+	ErrUnclosedPlaceholder = errors.New("unclosed placeholder")
+	ErrUnopenedPlaceholder = errors.New("closing braces without an opening placeholder")
+	ErrInvalidPlaceholder  = errors.New("invalid placeholder name")
+	ErrUndeclaredVariable  = errors.New("placeholder is not a declared variable")
+)
+
+// placeholderNamePattern matches the names which can be substituted into a prompt
+var placeholderNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidatePrompt checks that every {{...}} placeholder in the prompt content is well formed
+// and refers to one of the prompt's declared variables
+func ValidatePrompt(p protocol.Prompt) error {
+	content := p.Content
+	pos := 0
+	for pos < len(content) {
+		openIdx := strings.Index(content[pos:], "{{")
+		closeIdx := strings.Index(content[pos:], "}}")
+		if openIdx < 0 {
+			if closeIdx >= 0 {
+				return fmt.Errorf("prompt %s: %w at %s", p.ID, ErrUnopenedPlaceholder, position(content, pos+closeIdx))
+			}
+			return nil
+		}
+		if closeIdx >= 0 && closeIdx < openIdx {
+			return fmt.Errorf("prompt %s: %w at %s", p.ID, ErrUnopenedPlaceholder, position(content, pos+closeIdx))
+		}
+
+		start := pos + openIdx
+		end := strings.Index(content[start+2:], "}}")
+		if end < 0 {
+			return fmt.Errorf("prompt %s: %w at %s", p.ID, ErrUnclosedPlaceholder, position(content, start))
+		}
+		name := content[start+2 : start+2+end]
+		// another opening inside the placeholder means the first one was never closed
+		if strings.Contains(name, "{{") {
+			return fmt.Errorf("prompt %s: %w at %s", p.ID, ErrUnclosedPlaceholder, position(content, start))
+		}
+		if !placeholderNamePattern.MatchString(name) {
+			return fmt.Errorf("prompt %s: %w '{{%s}}' at %s", p.ID, ErrInvalidPlaceholder, name, position(content, start))
+		}
+		if _, ok := p.Variables[name]; !ok {
+			return fmt.Errorf("prompt %s: %w '{{%s}}' at %s", p.ID, ErrUndeclaredVariable, name, position(content, start))
+		}
+		pos = start + 2 + end + 2
+	}
+	return nil
+}
+
+// position describes an offset in content as a line and column for error messages
+func position(content string, offset int) string {
+	line := strings.Count(content[:offset], "\n") + 1
+	col := offset - strings.LastIndex(content[:offset], "\n")
+	return fmt.Sprintf("line %d column %d", line, col)
+}
@@ -0,0 +1,164 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// EnvPromptWatch enables hot reloading of prompts when set to true
+const EnvPromptWatch = "MCP_PROMPTS_WATCH"
+
+// DefaultWatchDebounce is how long the watcher waits for edits to settle before reloading
+const DefaultWatchDebounce = 250 * time.Millisecond
+
+// WatchEnabled reports whether prompt hot reloading has been enabled in the environment
+func WatchEnabled() bool {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv(EnvPromptWatch)))
+	return v == "1" || v == "true" || v == "yes"
+}
+
+// PromptWatcher reloads prompts from the registry directory as their files change
+type PromptWatcher struct {
+	registry *PromptRegistry
+	watcher  *fsnotify.Watcher
+	debounce time.Duration
+	onChange func([]protocol.Prompt)
+
+	mu      sync.Mutex
+	loaded  map[string]protocol.Prompt
+	pending map[string]bool
+	timer   *time.Timer
+	done    chan struct{}
+}
+
+// Watch starts watching the registry directory, calling onChange with the full list of
+// prompts whenever any of them are added, changed or removed.
+// Rapid edits are debounced, and a prompt whose file fails to load keeps its previously
+// loaded version. Call Close on the returned watcher to stop watching
+func (pr *PromptRegistry) Watch(debounce time.Duration, onChange func([]protocol.Prompt)) (*PromptWatcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fw.Add(pr.baseDir); err != nil {
+		fw.Close()
+		return nil, err
+	}
+
+	pw := &PromptWatcher{
+		registry: pr,
+		watcher:  fw,
+		debounce: debounce,
+		onChange: onChange,
+		loaded:   map[string]protocol.Prompt{},
+		pending:  map[string]bool{},
+		done:     make(chan struct{}),
+	}
+
+	// start from whatever currently loads successfully
+	current, err := pr.ListPrompts()
+	if err != nil {
+		logger.Warn("Failed to load prompts before watching", err)
+	}
+	for _, p := range current {
+		pw.loaded[p.ID] = p
+	}
+
+	go pw.run()
+	logger.Info("Watching for prompt changes in", pr.baseDir)
+	return pw, nil
+}
+
+// Close stops watching for changes
+func (pw *PromptWatcher) Close() error {
+	pw.mu.Lock()
+	if pw.timer != nil {
+		pw.timer.Stop()
+	}
+	pw.mu.Unlock()
+	close(pw.done)
+	return pw.watcher.Close()
+}
+
+// run handles file system events until the watcher is closed
+func (pw *PromptWatcher) run() {
+	for {
+		select {
+		case <-pw.done:
+			return
+		case event, ok := <-pw.watcher.Events:
+			if !ok {
+				return
+			}
+			if !strings.HasSuffix(event.Name, ".json") {
+				continue
+			}
+			id := strings.TrimSuffix(filepath.Base(event.Name), ".json")
+			pw.mu.Lock()
+			pw.pending[id] = true
+			// restart the debounce timer so a burst of edits causes a single reload
+			if pw.timer != nil {
+				pw.timer.Stop()
+			}
+			pw.timer = time.AfterFunc(pw.debounce, pw.reload)
+			pw.mu.Unlock()
+		case err, ok := <-pw.watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Warn("Prompt watcher error", err)
+		}
+	}
+}
+
+// reload reloads every prompt with pending changes and reports the new list if anything changed
+func (pw *PromptWatcher) reload() {
+	pw.mu.Lock()
+	pending := pw.pending
+	pw.pending = map[string]bool{}
+
+	changed := false
+	for id := range pending {
+		path, err := pw.registry.GetPromptPath(id)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if _, ok := pw.loaded[id]; ok {
+				delete(pw.loaded, id)
+				changed = true
+				logger.Info("Prompt removed", id)
+			}
+			continue
+		}
+		prompt, err := pw.registry.GetPrompt(id)
+		if err != nil {
+			logger.Error("Failed to reload prompt, keeping the previous version", id, err)
+			continue
+		}
+		pw.loaded[id] = *prompt
+		changed = true
+		logger.Info("Prompt reloaded", id)
+	}
+
+	if !changed {
+		pw.mu.Unlock()
+		return
+	}
+	list := make([]protocol.Prompt, 0, len(pw.loaded))
+	for _, p := range pw.loaded {
+		list = append(list, p)
+	}
+	pw.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	pw.onChange(list)
+}
@@ -2,7 +2,9 @@ package protocol
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 )
 
 /**
@@ -51,6 +53,9 @@ const (
 	MethodShowMessageRequest MethodType = "window/showMessageRequest"
 	MethodLogMessage         MethodType = "window/logMessage"
 
+	// Notification methods
+	MethodPromptsListChanged MethodType = "notifications/prompts/list_changed"
+
 	// Telemetry methods
 	MethodTelemetryEvent MethodType = "telemetry/event"
 
@@ -116,6 +121,60 @@ type JsonRpcError struct {
 	Data any `json:"data,omitempty"`
 }
 
+//////////////
+/// INITIALIZE
+//////////////
+
+// ServerCapabilities tells the client which features the server offers in the initialize response.
+// Capabilities the server doesn't have are left nil so that they are omitted
+// https://modelcontextprotocol.io/specification/2025-06-18/basic/lifecycle#capability-negotiation
+type ServerCapabilities struct {
+	Tools       *ToolsCapability       `json:"tools,omitempty"`
+	Prompts     *PromptsCapability     `json:"prompts,omitempty"`
+	Resources   *ResourcesCapability   `json:"resources,omitempty"`
+	Logging     *LoggingCapability     `json:"logging,omitempty"`
+	Completions *CompletionsCapability `json:"completions,omitempty"`
+}
+
+// ToolsCapability is present if the server offers tools
+type ToolsCapability struct {
+	// ListChanged is true if the server notifies the client when its tools change
+	ListChanged bool `json:"listChanged"`
+}
+
+// PromptsCapability is present if the server offers prompts
+type PromptsCapability struct {
+	// ListChanged is true if the server notifies the client when its prompts change
+	ListChanged bool `json:"listChanged"`
+}
+
+// ResourcesCapability is present if the server offers resources
+type ResourcesCapability struct {
+	// Subscribe is true if the client can subscribe to changes to individual resources
+	Subscribe bool `json:"subscribe,omitempty"`
+	// ListChanged is true if the server notifies the client when its resources change
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// LoggingCapability is present if the server sends log messages to the client
+type LoggingCapability struct{}
+
+// CompletionsCapability is present if the server offers argument completions
+type CompletionsCapability struct{}
+
+// ServerInfo names the server in the initialize response
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeResult is the result of the initialize request
+type InitializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      ServerInfo         `json:"serverInfo"`
+}
+
 //////////////
 /// TOOLS
 //////////////
@@ -132,11 +191,171 @@ type InputSchema struct {
 	AdditionalProperties bool                    `json:"additionalProperties"`
 }
 
+// OutputSchema describes the structure of the result returned by a tool
+type OutputSchema struct {
+	Type       string                  `json:"type"`
+	Properties map[string]ToolProperty `json:"properties,omitempty"`
+	Required   []string                `json:"required,omitempty"`
+}
+
 // Tool represents a tool that can be invoked by Amazon Q
 type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema InputSchema `json:"inputSchema"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	InputSchema  InputSchema      `json:"inputSchema"`
+	OutputSchema *OutputSchema    `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are hints to the client about how a tool behaves
+// so that it can, for example, warn the user before running a destructive tool
+// https://modelcontextprotocol.io/docs/concepts/tools#tool-annotations
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// ReadOnlyAnnotations returns the annotations for a tool which does not modify its environment
+func ReadOnlyAnnotations(title string, openWorld bool) *ToolAnnotations {
+	readOnly := true
+	return &ToolAnnotations{
+		Title:         title,
+		ReadOnlyHint:  &readOnly,
+		OpenWorldHint: &openWorld,
+	}
+}
+
+// MutatingAnnotations returns the annotations for a tool which modifies its environment
+// destructive should be true if the tool may overwrite or remove existing state
+func MutatingAnnotations(title string, destructive bool, idempotent bool, openWorld bool) *ToolAnnotations {
+	readOnly := false
+	return &ToolAnnotations{
+		Title:           title,
+		ReadOnlyHint:    &readOnly,
+		DestructiveHint: &destructive,
+		IdempotentHint:  &idempotent,
+		OpenWorldHint:   &openWorld,
+	}
+}
+
+// Validate checks a tool result against the output schema
+// Returns a list of violations, which is empty if the result conforms
+func (o *OutputSchema) Validate(result any) []string {
+	var violations []string
+	if o == nil {
+		return violations
+	}
+
+	// Round trip through JSON so we validate exactly what the client will receive
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return append(violations, "result cannot be marshalled: "+err.Error())
+	}
+	var decoded any
+	if err := json.Unmarshal(resultBytes, &decoded); err != nil {
+		return append(violations, "result cannot be unmarshalled: "+err.Error())
+	}
+
+	if !jsonTypeMatches(o.Type, decoded) {
+		return append(violations, fmt.Sprintf("result is not of type %s", o.Type))
+	}
+	fields, ok := decoded.(map[string]any)
+	if !ok {
+		return violations
+	}
+
+	for _, name := range o.Required {
+		if _, exists := fields[name]; !exists {
+			violations = append(violations, fmt.Sprintf("required field '%s' is missing", name))
+		}
+	}
+	for name, property := range o.Properties {
+		value, exists := fields[name]
+		if !exists || value == nil {
+			continue
+		}
+		if !jsonTypeMatches(property.Type, value) {
+			violations = append(violations, fmt.Sprintf("field '%s' is not of type %s", name, property.Type))
+		}
+	}
+	return violations
+}
+
+// ToolContent is an item of unstructured content in a tool result,
+// either text or a resource embedded in the result
+type ToolContent struct {
+	Type     string            `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	Resource *EmbeddedResource `json:"resource,omitempty"`
+}
+
+// EmbeddedResource is the contents of a resource returned inline by a tool.
+// Binary contents are base64 encoded into Blob
+type EmbeddedResource struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// ToolResult is the result of calling a tool which declares an output schema or returns content directly
+type ToolResult struct {
+	Content           []ToolContent `json:"content"`
+	StructuredContent any           `json:"structuredContent,omitempty"`
+}
+
+// NewToolResult returns the result of a tool as structured content if the tool declares an output schema,
+// along with the same JSON as text for clients which don't read structured content.
+// An error is returned if the result doesn't match the schema, as clients may reject it.
+// The result of a tool without an output schema is returned unchanged
+func NewToolResult(tool Tool, result any) (any, error) {
+	if tool.OutputSchema == nil {
+		return result, nil
+	}
+	if violations := tool.OutputSchema.Validate(result); len(violations) > 0 {
+		return nil, NewDataError(
+			fmt.Errorf("result of %s does not match its output schema: %s", tool.Name, strings.Join(violations, ", ")),
+			map[string]any{"violations": violations},
+		)
+	}
+	text, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("result of %s cannot be marshalled: %w", tool.Name, err)
+	}
+	return &ToolResult{
+		Content:           []ToolContent{{Type: "text", Text: string(text)}},
+		StructuredContent: result,
+	}, nil
+}
+
+// jsonTypeMatches returns true if the decoded JSON value is of the given JSON schema type
+func jsonTypeMatches(schemaType string, value any) bool {
+	switch schemaType {
+	case "", "any":
+		return true
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok || value == nil
+	case "array":
+		_, ok := value.([]any)
+		return ok || value == nil
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	default:
+		return true
+	}
 }
 
 // ToolsResponse represents the response to a tools discovery request
@@ -288,7 +507,53 @@ func WrapError(err error, code int) *JsonRpcError {
 	return &JsonRpcError{
 		Code:    code,
 		Message: msg,
+		Data:    ErrorData(err),
+	}
+}
+
+// DataError is an error carrying structured detail for the JSON-RPC error 'data' member
+// Data must never contain secrets such as API keys, it is returned verbatim to the client
+type DataError struct {
+	Err  error
+	Data map[string]any
+}
+
+func (e *DataError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DataError) Unwrap() error {
+	return e.Err
+}
+
+// NewDataError attaches structured detail to an error
+func NewDataError(err error, data map[string]any) error {
+	if err == nil {
+		return nil
+	}
+	return &DataError{Err: err, Data: data}
+}
+
+// ErrorData collects the structured detail from every DataError in the error chain
+// Outer errors take precedence over inner ones. Returns nil if there is no detail
+func ErrorData(err error) any {
+	data := map[string]any{}
+	for err != nil {
+		var dataErr *DataError
+		if !errors.As(err, &dataErr) {
+			break
+		}
+		for key, value := range dataErr.Data {
+			if _, exists := data[key]; !exists {
+				data[key] = value
+			}
+		}
+		err = dataErr.Err
+	}
+	if len(data) == 0 {
+		return nil
 	}
+	return data
 }
 
 // NewErrorResponse creates a new JSON-RPC 2.0 error response
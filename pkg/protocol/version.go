@@ -0,0 +1,48 @@
+package protocol
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SupportedProtocolVersions are the MCP protocol versions this server supports, oldest first
+var SupportedProtocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+// DefaultProtocolVersion is used when the client doesn't ask for a version or asks for one that can't be understood
+const DefaultProtocolVersion = "2024-11-05"
+
+// NegotiateProtocolVersion chooses the protocol version to use with a client.
+// A supported version is used as requested. Otherwise the latest supported version no newer than the
+// requested one is used, as versions are dated.
+// A version which isn't a date falls back to DefaultProtocolVersion, and one older than every supported
+// version is rejected as there is no version both sides understand
+func NegotiateProtocolVersion(requested string) (string, error) {
+	requested = strings.TrimSpace(requested)
+	if requested == "" {
+		return DefaultProtocolVersion, nil
+	}
+	for _, version := range SupportedProtocolVersions {
+		if version == requested {
+			return requested, nil
+		}
+	}
+	if _, err := time.Parse(time.DateOnly, requested); err != nil {
+		return DefaultProtocolVersion, nil
+	}
+
+	// dates in this format sort as strings
+	negotiated := ""
+	for _, version := range SupportedProtocolVersions {
+		if version <= requested {
+			negotiated = version
+		}
+	}
+	if negotiated == "" {
+		return "", NewDataError(
+			fmt.Errorf("unsupported protocol version %s, supported versions are %s", requested, strings.Join(SupportedProtocolVersions, ", ")),
+			map[string]any{"requested": requested, "supported": SupportedProtocolVersions},
+		)
+	}
+	return negotiated, nil
+}
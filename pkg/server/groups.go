@@ -0,0 +1,90 @@
+package server
+
+import (
+	"os"
+	"strings"
+
+	"github.com/richard-senior/mcp/internal/logger"
+)
+
+// EnvToolGroups is a comma separated list of the tool groups registered by RegisterDefaultTools,
+// e.g. "search,markdown". Unset, empty or "all" registers every group in ToolGroups, and
+// the OptionalToolGroups are only registered when they are listed
+const EnvToolGroups = "MCP_TOOL_GROUPS"
+
+// Tool groups which can be listed in EnvToolGroups
+const (
+	ToolGroupAll = "all"
+	// ToolGroupSearch is google_search, image_search and get_image
+	ToolGroupSearch = "search"
+	// ToolGroupMarkdown is html_2_markdown, html_2_markdown_file and local_html_2_markdown
+	ToolGroupMarkdown = "markdown"
+	// ToolGroupDebug is the go_debug_* tools
+	ToolGroupDebug = "debug"
+	// ToolGroupSvg is svg_path_info
+	ToolGroupSvg = "svg"
+	// ToolGroupThoughts is thoughts and thoughts_export, which are optional
+	ToolGroupThoughts = "thoughts"
+	// ToolGroupMeme is meme_tool, which is optional
+	ToolGroupMeme = "meme"
+	// ToolGroupPodds is reserved for the football prediction tools, which have no tools yet
+	ToolGroupPodds = "podds"
+	// ToolGroupDigitalIO is reserved for the digital IO tools, which are served by the separate _digital-io server
+	ToolGroupDigitalIO = "digitalio"
+)
+
+// ToolGroups lists the tool groups registered by default, in the order they are registered
+var ToolGroups = []string{
+	ToolGroupSearch,
+	ToolGroupMarkdown,
+	ToolGroupDebug,
+	ToolGroupSvg,
+	ToolGroupPodds,
+	ToolGroupDigitalIO,
+}
+
+// OptionalToolGroups lists the tool groups which are only registered when named in EnvToolGroups
+var OptionalToolGroups = []string{
+	ToolGroupThoughts,
+	ToolGroupMeme,
+}
+
+// EnabledToolGroups returns the tool groups enabled in the environment.
+// Unknown group names are logged and ignored
+func EnabledToolGroups() map[string]bool {
+	return ParseToolGroups(os.Getenv(EnvToolGroups))
+}
+
+// ParseToolGroups converts a comma separated list of tool groups to a set.
+// An empty list enables every group in ToolGroups, as does "all" which may be combined
+// with OptionalToolGroups, e.g. "all,thoughts"
+func ParseToolGroups(list string) map[string]bool {
+	defaults := map[string]bool{}
+	for _, group := range ToolGroups {
+		defaults[group] = true
+	}
+	if strings.TrimSpace(list) == "" {
+		return defaults
+	}
+	optional := map[string]bool{}
+	for _, group := range OptionalToolGroups {
+		optional[group] = true
+	}
+
+	enabled := map[string]bool{}
+	for _, group := range strings.Split(list, ",") {
+		group = strings.ToLower(strings.TrimSpace(group))
+		switch {
+		case group == "":
+		case group == ToolGroupAll:
+			for g := range defaults {
+				enabled[g] = true
+			}
+		case defaults[group], optional[group]:
+			enabled[group] = true
+		default:
+			logger.Warn("Ignoring unknown tool group", group, "in", EnvToolGroups)
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/util"
+)
+
+// maxMethodSuggestions is the most near matches offered for an unknown method
+const maxMethodSuggestions = 3
+
+// methodAliases maps method names used by older or non-conforming clients to the methods they mean
+var methodAliases = map[string]protocol.MethodType{
+	"tools/invoke":  protocol.MethodToolsCall,
+	"tool/call":     protocol.MethodToolsCall,
+	"tool/list":     protocol.MethodToolsList,
+	"prompt/list":   protocol.MethodPromptsList,
+	"prompt/get":    protocol.MethodPromptsGet,
+	"resource/list": protocol.MethodResourcesList,
+}
+
+// resolveMethodAlias returns the method an alias stands for, or the method unchanged
+func resolveMethodAlias(method string) string {
+	if canonical, ok := methodAliases[method]; ok {
+		logger.Info("Treating method", method, "as", canonical)
+		return string(canonical)
+	}
+	return method
+}
+
+// SuggestMethods returns up to three of the candidates which are close to the given method,
+// closest first, so that a client using a slightly wrong method name can be told what it meant.
+// Differences in case and surrounding whitespace are ignored
+func SuggestMethods(method string, candidates []string) []string {
+	normalised := strings.ToLower(strings.TrimSpace(method))
+	// allow roughly one mistake in every four characters
+	maxDistance := max(2, len(normalised)/4)
+
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		d := util.LevenshteinDistance(normalised, strings.ToLower(candidate))
+		if d <= maxDistance {
+			matches = append(matches, match{candidate, d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var suggestions []string
+	for i := 0; i < len(matches) && i < maxMethodSuggestions; i++ {
+		suggestions = append(suggestions, matches[i].name)
+	}
+	return suggestions
+}
+
+// methodNotFoundError builds the error for an unknown method, suggesting near matches from the
+// registered methods. Tools aren't suggested as they should be called through tools/call
+func (s *Server) methodNotFoundError(method string) *protocol.JsonRpcError {
+	tools := map[string]bool{}
+	for _, tool := range s.GetTools() {
+		tools[tool.Name] = true
+	}
+	var methods []string
+	for name := range s.handlers {
+		if !tools[name] {
+			methods = append(methods, name)
+		}
+	}
+
+	message := fmt.Sprintf("Method not found: %s", method)
+	data := map[string]any{"method": method}
+	if suggestions := SuggestMethods(method, methods); len(suggestions) > 0 {
+		message += fmt.Sprintf(", did you mean %s?", strings.Join(suggestions, " or "))
+		data["suggestions"] = suggestions
+	}
+	return protocol.CreateError(protocol.ErrMethodNotFound, message, data)
+}
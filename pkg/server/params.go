@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// DecodeParams decodes request params into target, which must be a pointer.
+// Params straight from a request are json.RawMessage and are decoded without copying,
+// anything else (a map from invoke_tool for example) is marshalled first.
+// Errors name the field which couldn't be decoded and carry it in their data
+func DecodeParams(params any, target any) error {
+	var data []byte
+	switch p := params.(type) {
+	case nil:
+		return nil
+	case json.RawMessage:
+		data = p
+	case []byte:
+		data = p
+	default:
+		var err error
+		if data, err = json.Marshal(p); err != nil {
+			return fmt.Errorf("params can't be encoded: %w", err)
+		}
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	err := json.Unmarshal(data, target)
+	if err == nil {
+		return nil
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return protocol.NewDataError(
+			fmt.Errorf("%s must be %s, not %s", typeErr.Field, jsonTypeName(typeErr.Type), typeErr.Value),
+			map[string]any{"field": typeErr.Field},
+		)
+	}
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("params must be %s, not %s", jsonTypeName(typeErr.Type), typeErr.Value)
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("params are not valid JSON at offset %d: %w", syntaxErr.Offset, err)
+	}
+	return err
+}
+
+// jsonTypeName describes a Go type as the JSON type a client should send
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a " + t.String()
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -17,13 +18,31 @@ import (
 	"github.com/richard-senior/mcp/pkg/transport"
 )
 
+// EnvToolPrefix sets the prefix added to the name of each default tool, which may be empty for no prefix
+const EnvToolPrefix = "MCP_TOOL_PREFIX"
+
+// DefaultToolPrefix is used when EnvToolPrefix isn't set
+const DefaultToolPrefix = "mcp___"
+
+// ToolPrefix returns the tool name prefix set in the environment, or DefaultToolPrefix if it isn't set
+func ToolPrefix() string {
+	if prefix, ok := os.LookupEnv(EnvToolPrefix); ok {
+		return strings.TrimSpace(prefix)
+	}
+	return DefaultToolPrefix
+}
+
 // Server represents an MCP server
 type Server struct {
-	transport transport.Transport
-	handlers  map[string]HandlerFunc
-	tools     []protocol.Tool
-	resources []protocol.Resource
-	prompts   []protocol.Prompt
+	// ToolPrefix is added to the names of tools registered with RegisterPrefixedTool
+	ToolPrefix string
+	transport  transport.Transport
+	handlers   map[string]HandlerFunc
+	tools      []protocol.Tool
+	resources  []protocol.Resource
+	prompts    []protocol.Prompt
+	// promptWatcher reloads prompts as they are edited when MCP_PROMPTS_WATCH is enabled
+	promptWatcher *prompts.PromptWatcher
 }
 
 // HandlerFunc is a function that handles an MCP request
@@ -51,13 +70,7 @@ func GetInstance() *Server {
 // InitInstance initializes the singleton instance of the Server with the specified transport
 func InitInstance(t transport.Transport) *Server {
 	once.Do(func() {
-		instance = &Server{
-			transport: t,
-			handlers:  make(map[string]HandlerFunc),
-			tools:     []protocol.Tool{},
-			resources: []protocol.Resource{},
-			prompts:   []protocol.Prompt{},
-		}
+		instance = NewServer(t)
 		// Register default tools and resources
 		instance.RegisterDefaultTools()
 		instance.RegisterDefaultResources()
@@ -66,6 +79,19 @@ func InitInstance(t transport.Transport) *Server {
 	return instance
 }
 
+// NewServer creates a server with no tools, resources or prompts registered,
+// using the tool prefix from the environment. Most callers want the singleton from InitInstance
+func NewServer(t transport.Transport) *Server {
+	return &Server{
+		ToolPrefix: ToolPrefix(),
+		transport:  t,
+		handlers:   make(map[string]HandlerFunc),
+		tools:      []protocol.Tool{},
+		resources:  []protocol.Resource{},
+		prompts:    []protocol.Prompt{},
+	}
+}
+
 // RegisterTool registers a tool with the server
 func (s *Server) RegisterTool(tool protocol.Tool, handler HandlerFunc) {
 	mu.Lock()
@@ -76,6 +102,28 @@ func (s *Server) RegisterTool(tool protocol.Tool, handler HandlerFunc) {
 	logger.Info("Registered tool:", tool.Name)
 }
 
+// RegisterPrefixedTool registers a tool with the server under its name with ToolPrefix added
+func (s *Server) RegisterPrefixedTool(tool protocol.Tool, handler HandlerFunc) {
+	tool.Name = s.ToolPrefix + tool.Name
+	s.RegisterTool(tool, handler)
+}
+
+// ToolHandler returns the handler of a registered tool, or nil if there isn't one.
+// The name may be given with or without ToolPrefix
+func (s *Server) ToolHandler(name string) HandlerFunc {
+	if handler := s.handlers[name]; handler != nil {
+		return handler
+	}
+	if s.ToolPrefix == "" {
+		return nil
+	}
+	if stripped, ok := strings.CutPrefix(name, s.ToolPrefix); ok {
+		logger.Info("Trying with stripped name:", stripped)
+		return s.handlers[stripped]
+	}
+	return s.handlers[s.ToolPrefix+name]
+}
+
 // RegisterResource registers a resource with the server
 func (s *Server) RegisterResource(resource protocol.Resource) {
 	mu.Lock()
@@ -92,99 +140,141 @@ func (s *Server) GetTools() []protocol.Tool {
 	return s.tools
 }
 
-// RegisterDefaultTools registers all the default tools with the server
+// RegisterDefaultTools registers the default tools in the groups enabled by MCP_TOOL_GROUPS,
+// every group by default, along with the prompt preview and self test tools which are always available
 func (s *Server) RegisterDefaultTools() {
 	logger.Info("Registering default tools...")
+	groups := EnabledToolGroups()
+
+	if groups[ToolGroupSearch] {
+		s.registerSearchTools()
+	}
+	if groups[ToolGroupMarkdown] {
+		s.registerMarkdownTools()
+	}
+
+	// Register prompt preview tool
+	promptPreviewTool := tools.PromptPreviewTool()
+	s.RegisterPrefixedTool(promptPreviewTool, tools.HandlePromptPreview)
+
+	// Register connectivity self test tool
+	selfTestTool := tools.SelfTestTool()
+	s.RegisterPrefixedTool(selfTestTool, tools.HandleSelfTest)
+
+	if groups[ToolGroupDebug] {
+		s.registerDebugTools()
+	}
+	if groups[ToolGroupSvg] {
+		s.registerSvgTools()
+	}
+	if groups[ToolGroupThoughts] {
+		s.registerThoughtsTools()
+	}
+	if groups[ToolGroupMeme] {
+		s.registerMemeTools()
+	}
+	// podds and digitalio have no tools in this server yet
+
+	// Register built-in handlers
+	s.handlers[string(protocol.MethodInitialize)] = s.handleInitialize
+	s.handlers[string(protocol.MethodInitialized)] = s.handleInitialized
+	s.handlers[string(protocol.MethodToolsList)] = s.handleToolsList
+	//s.handlers[string(protocol.MethodResourcesList)] = s.handleResourcesList
+	s.handlers[string(protocol.MethodToolsCall)] = s.handleToolsCall
+	s.handlers[string(protocol.MethodPromptsList)] = s.handlePromptsList
+	s.handlers[string(protocol.MethodPromptsGet)] = s.handlePromptsGet
+}
 
+// registerSearchTools registers the web and image search tools
+func (s *Server) registerSearchTools() {
 	// Register Google search tool
 	googleSearchTool := tools.GoogleSearchTool()
-	googleSearchTool.Name = "mcp___" + googleSearchTool.Name
-	s.RegisterTool(googleSearchTool, tools.HandleGoogleSearchTool)
+	s.RegisterPrefixedTool(googleSearchTool, tools.HandleGoogleSearchTool)
+
+	// Register general image search tool
+	imageSearchTool := tools.ImageSearchTool()
+	s.RegisterPrefixedTool(imageSearchTool, tools.HandleImageSearchTool)
 
+	// Register Wikipedia image tool
+	wikipediaImageTool := tools.WikipediaImageTool()
+	s.RegisterPrefixedTool(wikipediaImageTool, tools.HandleWikipediaImageTool)
+}
+
+// registerMarkdownTools registers the html to markdown conversion tools
+func (s *Server) registerMarkdownTools() {
 	// Register Html to Markdown tools
 	html2MarkdownTool := tools.HTMLToMarkdownTool()
-	html2MarkdownTool.Name = "mcp___" + html2MarkdownTool.Name
-	s.RegisterTool(html2MarkdownTool, tools.HandleURLToMarkdown)
+	s.RegisterPrefixedTool(html2MarkdownTool, tools.HandleURLToMarkdown)
 
 	html2MarkdownFileTool := tools.HTMLToMarkdownFileTool()
-	html2MarkdownFileTool.Name = "mcp___" + html2MarkdownFileTool.Name
-	s.RegisterTool(html2MarkdownFileTool, tools.HandleUrlToMarkdownFile)
-
-	// Register Wikipedia image tool
-	wikipediaImageTool := tools.WikipediaImageTool()
-	wikipediaImageTool.Name = "mcp___" + wikipediaImageTool.Name
-	s.RegisterTool(wikipediaImageTool, tools.HandleWikipediaImageTool)
+	s.RegisterPrefixedTool(html2MarkdownFileTool, tools.HandleUrlToMarkdownFile)
 
-	// Register Meme tool
-	/*
-			memeTool := tools.NewMemeTool()
-			memeTool.Name = "mcp___" + memeTool.Name
-			s.RegisterTool(memeTool, tools.HandleMemeTool)
-
-		// Register Thoughts tool
-		thoughtsTool := tools.NewThoughtsTool()
-		thoughtsTool.Name = "mcp___" + thoughtsTool.Name
-		s.RegisterTool(thoughtsTool, tools.HandleThoughts)
-	*/
+	localHTML2MarkdownTool := tools.LocalHTMLToMarkdownTool()
+	s.RegisterPrefixedTool(localHTML2MarkdownTool, tools.HandleLocalHTMLToMarkdown)
+}
 
+// registerDebugTools registers the go debugger tools
+func (s *Server) registerDebugTools() {
 	// Register Go Debug tools
 	goDebugLaunchTool := tools.GoDebugLaunchTool()
-	goDebugLaunchTool.Name = "mcp___" + goDebugLaunchTool.Name
-	s.RegisterTool(goDebugLaunchTool, tools.HandleGoDebugLaunch)
+	s.RegisterPrefixedTool(goDebugLaunchTool, tools.HandleGoDebugLaunch)
 
 	goDebugContinueTool := tools.GoDebugContinueTool()
-	goDebugContinueTool.Name = "mcp___" + goDebugContinueTool.Name
-	s.RegisterTool(goDebugContinueTool, tools.HandleGoDebugContinue)
+	s.RegisterPrefixedTool(goDebugContinueTool, tools.HandleGoDebugContinue)
 
 	goDebugStepTool := tools.GoDebugStepTool()
-	goDebugStepTool.Name = "mcp___" + goDebugStepTool.Name
-	s.RegisterTool(goDebugStepTool, tools.HandleGoDebugStep)
+	s.RegisterPrefixedTool(goDebugStepTool, tools.HandleGoDebugStep)
 
 	goDebugStepOverTool := tools.GoDebugStepOverTool()
-	goDebugStepOverTool.Name = "mcp___" + goDebugStepOverTool.Name
-	s.RegisterTool(goDebugStepOverTool, tools.HandleGoDebugStepOver)
+	s.RegisterPrefixedTool(goDebugStepOverTool, tools.HandleGoDebugStepOver)
 
 	goDebugStepOutTool := tools.GoDebugStepOutTool()
-	goDebugStepOutTool.Name = "mcp___" + goDebugStepOutTool.Name
-	s.RegisterTool(goDebugStepOutTool, tools.HandleGoDebugStepOut)
+	s.RegisterPrefixedTool(goDebugStepOutTool, tools.HandleGoDebugStepOut)
 
 	goDebugSetBreakpointTool := tools.GoDebugSetBreakpointTool()
-	goDebugSetBreakpointTool.Name = "mcp___" + goDebugSetBreakpointTool.Name
-	s.RegisterTool(goDebugSetBreakpointTool, tools.HandleGoDebugSetBreakpoint)
+	s.RegisterPrefixedTool(goDebugSetBreakpointTool, tools.HandleGoDebugSetBreakpoint)
 
 	goDebugListBreakpointsTool := tools.GoDebugListBreakpointsTool()
-	goDebugListBreakpointsTool.Name = "mcp___" + goDebugListBreakpointsTool.Name
-	s.RegisterTool(goDebugListBreakpointsTool, tools.HandleGoDebugListBreakpoints)
+	s.RegisterPrefixedTool(goDebugListBreakpointsTool, tools.HandleGoDebugListBreakpoints)
 
 	goDebugRemoveBreakpointTool := tools.GoDebugRemoveBreakpointTool()
-	goDebugRemoveBreakpointTool.Name = "mcp___" + goDebugRemoveBreakpointTool.Name
-	s.RegisterTool(goDebugRemoveBreakpointTool, tools.HandleGoDebugRemoveBreakpoint)
+	s.RegisterPrefixedTool(goDebugRemoveBreakpointTool, tools.HandleGoDebugRemoveBreakpoint)
 
 	goDebugEvalVariableTool := tools.GoDebugEvalVariableTool()
-	goDebugEvalVariableTool.Name = "mcp___" + goDebugEvalVariableTool.Name
-	s.RegisterTool(goDebugEvalVariableTool, tools.HandleGoDebugEvalVariable)
+	s.RegisterPrefixedTool(goDebugEvalVariableTool, tools.HandleGoDebugEvalVariable)
 
 	goDebugCloseTool := tools.GoDebugCloseTool()
-	goDebugCloseTool.Name = "mcp___" + goDebugCloseTool.Name
-	s.RegisterTool(goDebugCloseTool, tools.HandleGoDebugClose)
+	s.RegisterPrefixedTool(goDebugCloseTool, tools.HandleGoDebugClose)
 
 	goDebugGetOutputTool := tools.GoDebugGetOutputTool()
-	goDebugGetOutputTool.Name = "mcp___" + goDebugGetOutputTool.Name
-	s.RegisterTool(goDebugGetOutputTool, tools.HandleGoDebugGetOutput)
+	s.RegisterPrefixedTool(goDebugGetOutputTool, tools.HandleGoDebugGetOutput)
+}
 
+// registerSvgTools registers the SVG path tools
+func (s *Server) registerSvgTools() {
 	// Register SVG Tools
+	svgPathInfoTool := tools.SvgPathInfoTool()
+	s.RegisterPrefixedTool(svgPathInfoTool, tools.HandleSvgPathInfo)
+
 	//svgTool := tools.NewSvgTool()
-	//svgTool.Name = "mcp___" + svgTool.Name
-	//s.RegisterTool(svgTool, tools.HandleSvgTool)
+	//s.RegisterPrefixedTool(svgTool, tools.HandleSvgTool)
+}
 
-	// Register built-in handlers
-	s.handlers[string(protocol.MethodInitialize)] = s.handleInitialize
-	s.handlers[string(protocol.MethodInitialized)] = s.handleInitialized
-	s.handlers[string(protocol.MethodToolsList)] = s.handleToolsList
-	//s.handlers[string(protocol.MethodResourcesList)] = s.handleResourcesList
-	s.handlers[string(protocol.MethodToolsCall)] = s.handleToolsCall
-	s.handlers[string(protocol.MethodPromptsList)] = s.handlePromptsList
-	s.handlers[string(protocol.MethodPromptsGet)] = s.handlePromptsGet
+// registerThoughtsTools registers the sequential thinking tools
+func (s *Server) registerThoughtsTools() {
+	// Register Thoughts tool
+	thoughtsTool := tools.NewThoughtsTool()
+	s.RegisterPrefixedTool(thoughtsTool, tools.HandleThoughts)
+
+	thoughtsExportTool := tools.ThoughtsExportTool()
+	s.RegisterPrefixedTool(thoughtsExportTool, tools.HandleThoughtsExport)
+}
+
+// registerMemeTools registers the meme tool
+func (s *Server) registerMemeTools() {
+	// Register Meme tool
+	memeTool := tools.NewMemeTool()
+	s.RegisterPrefixedTool(memeTool, tools.HandleMemeTool)
 }
 
 // RegisterDefaultResources registers all the default resources with the server
@@ -207,6 +297,55 @@ func (s *Server) RegisterDefaultPrompts() {
 	mu.Unlock()
 
 	logger.Info("Loaded prompts from registry", len(promptList))
+
+	if prompts.WatchEnabled() && s.promptWatcher == nil {
+		watcher, err := registry.Watch(prompts.DefaultWatchDebounce, s.handlePromptsChanged)
+		if err != nil {
+			logger.Error("Failed to watch prompts for changes", err)
+			return
+		}
+		s.promptWatcher = watcher
+	}
+}
+
+// handlePromptsChanged replaces the registered prompts and tells the client the list has changed
+func (s *Server) handlePromptsChanged(promptList []protocol.Prompt) {
+	mu.Lock()
+	s.prompts = promptList
+	mu.Unlock()
+
+	logger.Info("Prompts reloaded", len(promptList))
+	s.notify(protocol.MethodPromptsListChanged)
+}
+
+// notify sends a notification to the client
+func (s *Server) notify(method protocol.MethodType) {
+	notification, err := protocol.NewJsonRpcNotification(string(method), nil)
+	if err != nil {
+		logger.Error("Failed to create notification", method, err)
+		return
+	}
+	if err := s.transport.WriteNotification(notification); err != nil {
+		logger.Error("Failed to send notification", method, err)
+	}
+}
+
+// getPrompts returns a copy of the registered prompts
+func (s *Server) getPrompts() []protocol.Prompt {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]protocol.Prompt{}, s.prompts...)
+}
+
+// findPrompt returns a registered prompt by ID, falling back to the registry for
+// prompts added since the server loaded them
+func (s *Server) findPrompt(id string) (*protocol.Prompt, error) {
+	for _, prompt := range s.getPrompts() {
+		if prompt.ID == id {
+			return &prompt, nil
+		}
+	}
+	return prompts.GetGlobalRegistry().GetPrompt(id)
 }
 
 // RegisterDefaultResources registers all the default resources with the server
@@ -258,6 +397,17 @@ func (s *Server) ProcessRequests() error {
 	for {
 		// Read a request
 		req, err := s.transport.ReadRequest()
+		if errors.Is(err, transport.ErrMessageTooLarge) {
+			// the oversized message has been skipped so carry on with the next one
+			resp := &protocol.JsonRpcResponse{
+				JsonRPC: protocol.JsonRpcVersion,
+				Error:   protocol.CreateError(protocol.ErrInvalidRequest, err.Error(), nil),
+			}
+			if err := s.transport.WriteResponse(resp); err != nil {
+				return err
+			}
+			continue
+		}
 		if err != nil {
 			return err
 		}
@@ -325,28 +475,25 @@ func (s *Server) handleRequest(req *protocol.JsonRpcRequest) *protocol.JsonRpcRe
 		// Log the requested tool name
 		logger.Info("Tool invocation requested for:", toolName)
 
-		// Try to find the handler directly
-		handler = s.handlers[toolName]
-
-		// If not found, try to strip the prefix if it exists (for mcp___ prefix)
-		if handler == nil && strings.HasPrefix(toolName, "mcp___") {
-			strippedName := strings.TrimPrefix(toolName, "mcp___")
-			logger.Info("Trying with stripped name:", strippedName)
-			handler = s.handlers[strippedName]
-		}
+		// Find the handler, with or without the tool prefix
+		handler = s.ToolHandler(toolName)
 
 		params = invokeParams["parameters"]
 	} else {
 		// For other methods, use the method name directly
-		handler = s.handlers[req.Method]
+		handler = s.handlers[resolveMethodAlias(req.Method)]
 		params = req.Params
 	}
 
 	// If no handler is found, return an error
 	if handler == nil {
-		resp.Error = &protocol.JsonRpcError{
-			Code:    protocol.ErrMethodNotFound,
-			Message: fmt.Sprintf("Method not found: %s", req.Method),
+		if req.Method == string(protocol.MethodInvokeTool) {
+			resp.Error = &protocol.JsonRpcError{
+				Code:    protocol.ErrMethodNotFound,
+				Message: fmt.Sprintf("Method not found: %s", req.Method),
+			}
+		} else {
+			resp.Error = s.methodNotFoundError(req.Method)
 		}
 		return resp
 	}
@@ -359,9 +506,16 @@ func (s *Server) handleRequest(req *protocol.JsonRpcRequest) *protocol.JsonRpcRe
 	}
 
 	if err != nil {
+		// handlers may return a JsonRpcError to choose their own error code
+		var rpcErr *protocol.JsonRpcError
+		if errors.As(err, &rpcErr) {
+			resp.Error = rpcErr
+			return resp
+		}
 		resp.Error = &protocol.JsonRpcError{
 			Code:    protocol.ErrToolExecutionFailed,
 			Message: err.Error(),
+			Data:    protocol.ErrorData(err),
 		}
 		return resp
 	}
@@ -398,7 +552,7 @@ func (s *Server) handlePromptsList(params interface{}) (interface{}, error) {
 	}
 
 	var promptList []PromptListEntry
-	for _, prompt := range s.prompts {
+	for _, prompt := range s.getPrompts() {
 		promptList = append(promptList, PromptListEntry{
 			Name:        prompt.ID, // Use ID as name for MCP compatibility
 			Description: prompt.Description,
@@ -427,34 +581,20 @@ func (s *Server) handlePromptsGet(params interface{}) (interface{}, error) {
 	}
 
 	var getParams PromptsGetParams
-
-	// Convert params to JSON and then unmarshal it
-	paramsBytes, err := json.Marshal(params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal params: %v", err)
-	}
-
-	if err := json.Unmarshal(paramsBytes, &getParams); err != nil {
-		return nil, fmt.Errorf("invalid prompts/get parameters: %v", err)
+	if err := DecodeParams(params, &getParams); err != nil {
+		return nil, protocol.WrapError(fmt.Errorf("invalid prompts/get parameters: %w", err), protocol.ErrInvalidParams)
 	}
 
 	logger.Info("Prompt get requested for:", getParams.Name)
 
-	// Get the prompt from the registry
-	registry := prompts.GetGlobalRegistry()
-	prompt, err := registry.GetPrompt(getParams.Name)
+	// Get the prompt, preferring the loaded version in case its file has since been broken
+	prompt, err := s.findPrompt(getParams.Name)
 	if err != nil {
 		return nil, fmt.Errorf("prompt not found: %s", getParams.Name)
 	}
 
 	// Process the prompt content with any provided arguments
-	content := prompt.Content
-	if getParams.Arguments != nil {
-		for key, value := range getParams.Arguments {
-			placeholder := fmt.Sprintf("{{%s}}", key)
-			content = strings.ReplaceAll(content, placeholder, value)
-		}
-	}
+	content, _ := prompts.RenderPrompt(*prompt, getParams.Arguments)
 
 	// Return the processed prompt
 	response := struct {
@@ -514,7 +654,7 @@ func (s *Server) handleInitialize(params interface{}) (interface{}, error) {
 	logger.Info("Handling initialize request with", len(s.tools), "tools and", len(s.prompts), "prompts registered")
 
 	// Extract protocol version from request params
-	var requestedProtocolVersion string = "2024-11-05" // fallback
+	var requestedProtocolVersion string
 
 	// Parse the params if they're JSON bytes
 	var paramsMap map[string]interface{}
@@ -527,10 +667,18 @@ func (s *Server) handleInitialize(params interface{}) (interface{}, error) {
 
 		if version, exists := paramsMap["protocolVersion"].(string); exists {
 			requestedProtocolVersion = version
-			logger.Info("Using requested protocol version:", requestedProtocolVersion)
+			logger.Info("Client requested protocol version:", requestedProtocolVersion)
 		}
 	}
-	logger.Info("Final protocol version to use:", requestedProtocolVersion)
+	protocolVersion, err := protocol.NegotiateProtocolVersion(requestedProtocolVersion)
+	if err != nil {
+		logger.Error("Failed to negotiate protocol version", err)
+		return nil, protocol.WrapError(err, protocol.ErrInvalidParams)
+	}
+	if requestedProtocolVersion != "" && protocolVersion != requestedProtocolVersion {
+		logger.Warn("Client requested unsupported protocol version", requestedProtocolVersion, "using", protocolVersion)
+	}
+	logger.Info("Final protocol version to use:", protocolVersion)
 
 	// Log the incoming parameters
 	if paramsBytes, err := json.Marshal(params); err == nil {
@@ -539,34 +687,10 @@ func (s *Server) handleInitialize(params interface{}) (interface{}, error) {
 		logger.Warn("Failed to marshal initialize params:", err)
 	}
 
-	capabilities := map[string]any{}
-
-	// Only include capabilities if we have the corresponding features
-	if len(s.tools) > 0 {
-		capabilities["tools"] = map[string]any{
-			"listChanged": true,
-		}
-	}
-	if len(s.prompts) > 0 {
-		capabilities["prompts"] = map[string]any{
-			"listChanged": true,
-		}
-	}
-
-	initializeResponse := struct {
-		ProtocolVersion string         `json:"protocolVersion"`
-		Capabilities    map[string]any `json:"capabilities"`
-		ServerInfo      struct {
-			Name    string `json:"name"`
-			Version string `json:"version"`
-		} `json:"serverInfo"`
-	}{
-		ProtocolVersion: requestedProtocolVersion,
-		Capabilities:    capabilities,
-		ServerInfo: struct {
-			Name    string `json:"name"`
-			Version string `json:"version"`
-		}{
+	initializeResponse := protocol.InitializeResult{
+		ProtocolVersion: protocolVersion,
+		Capabilities:    s.capabilities(),
+		ServerInfo: protocol.ServerInfo{
 			Name:    "mcp",
 			Version: "1.0.0",
 		},
@@ -582,6 +706,25 @@ func (s *Server) handleInitialize(params interface{}) (interface{}, error) {
 	return initializeResponse, nil
 }
 
+// capabilities returns the capabilities of the server, based on the tools, prompts and handlers registered
+func (s *Server) capabilities() protocol.ServerCapabilities {
+	mu.Lock()
+	defer mu.Unlock()
+
+	capabilities := protocol.ServerCapabilities{}
+	// Only include capabilities if we have the corresponding features
+	if len(s.tools) > 0 {
+		capabilities.Tools = &protocol.ToolsCapability{ListChanged: true}
+	}
+	if len(s.prompts) > 0 {
+		capabilities.Prompts = &protocol.PromptsCapability{ListChanged: true}
+	}
+	if len(s.resources) > 0 && s.handlers[string(protocol.MethodResourcesList)] != nil {
+		capabilities.Resources = &protocol.ResourcesCapability{}
+	}
+	return capabilities
+}
+
 // handleInitialized handles the initialized notification
 // 'initialized' Does not require a response
 func (s *Server) handleInitialized(params interface{}) (interface{}, error) {
@@ -600,39 +743,37 @@ func (s *Server) handleToolsCall(params any) (any, error) {
 	}
 
 	var toolCallParams ToolCallParams
-
-	// Convert params to JSON and then unmarshal it
-	paramsBytes, err := json.Marshal(params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal params: %v", err)
-	}
-
-	if err := json.Unmarshal(paramsBytes, &toolCallParams); err != nil {
-		return nil, fmt.Errorf("invalid tools/call parameters: %v", err)
+	if err := DecodeParams(params, &toolCallParams); err != nil {
+		return nil, protocol.WrapError(fmt.Errorf("invalid tools/call parameters: %w", err), protocol.ErrInvalidParams)
 	}
 
 	logger.Info("Tool call requested for:", toolCallParams.Name)
 
 	// Look up the tool handler
 	toolName := toolCallParams.Name
-	handler := s.handlers[toolName]
+	handler := s.ToolHandler(toolName)
 
-	// If not found, try to strip the prefix if it exists (for mcp___ prefix)
-	if handler == nil && strings.HasPrefix(toolName, "mcp___") {
-		strippedName := strings.TrimPrefix(toolName, "mcp___")
-		logger.Info("Trying with stripped name:", strippedName)
-		handler = s.handlers[strippedName]
-	}
-
-	// If still no handler is found, return an error
+	// If no handler is found, return an error
 	if handler == nil {
-		return nil, fmt.Errorf("tool not found: %s", toolName)
+		return nil, protocol.NewDataError(fmt.Errorf("tool not found: %s", toolName), map[string]any{"tool": toolName})
 	}
 
 	// Execute the tool with the provided arguments
 	result, err := handler(toolCallParams.Arguments)
 	if err != nil {
-		return nil, fmt.Errorf("tool execution failed: %v", err)
+		return nil, protocol.NewDataError(fmt.Errorf("tool execution failed: %w", err), map[string]any{"tool": toolName})
+	}
+
+	// Tools with an output schema must return structured content which matches it
+	for _, tool := range s.GetTools() {
+		if tool.Name != toolName && tool.Name != s.ToolPrefix+toolName {
+			continue
+		}
+		structured, err := protocol.NewToolResult(tool, result)
+		if err != nil {
+			return nil, protocol.NewDataError(fmt.Errorf("tool returned an invalid result: %w", err), map[string]any{"tool": toolName})
+		}
+		return structured, nil
 	}
 
 	return result, nil
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -16,6 +17,24 @@ var (
 	debugMutex  sync.Mutex
 )
 
+// delveErrorType returns the type name of the innermost error, which identifies the Delve failure
+func delveErrorType(err error) string {
+	for errors.Unwrap(err) != nil {
+		err = errors.Unwrap(err)
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// debugError attaches the type of the Delve error behind err to its error data.
+// Errors which didn't come from Delve are returned unchanged
+func debugError(err error) error {
+	var delveErr *debugger.DelveError
+	if !errors.As(err, &delveErr) {
+		return err
+	}
+	return protocol.NewDataError(err, map[string]any{"errorType": delveErrorType(delveErr.Err)})
+}
+
 func getDebugClient() *debugger.Client {
 	debugMutex.Lock()
 	defer debugMutex.Unlock()
@@ -45,6 +64,7 @@ func GoDebugLaunchTool() protocol.Tool {
 			},
 			Required: []string{"program"},
 		},
+		Annotations: protocol.MutatingAnnotations("Launch Debugger", false, false, false),
 	}
 }
 
@@ -101,6 +121,7 @@ func GoDebugContinueTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.MutatingAnnotations("Debugger Continue", false, false, false),
 	}
 }
 
@@ -136,6 +157,7 @@ func GoDebugStepTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.MutatingAnnotations("Debugger Step", false, false, false),
 	}
 }
 
@@ -154,6 +176,7 @@ func GoDebugStepOverTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.MutatingAnnotations("Debugger Step Over", false, false, false),
 	}
 }
 
@@ -172,6 +195,7 @@ func GoDebugStepOutTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.MutatingAnnotations("Debugger Step Out", false, false, false),
 	}
 }
 
@@ -200,6 +224,7 @@ func GoDebugSetBreakpointTool() protocol.Tool {
 			},
 			Required: []string{"file", "line"},
 		},
+		Annotations: protocol.MutatingAnnotations("Set Breakpoint", false, true, false),
 	}
 }
 
@@ -241,6 +266,7 @@ func GoDebugListBreakpointsTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("List Breakpoints", false),
 	}
 }
 
@@ -265,6 +291,7 @@ func GoDebugRemoveBreakpointTool() protocol.Tool {
 			},
 			Required: []string{"id"},
 		},
+		Annotations: protocol.MutatingAnnotations("Remove Breakpoint", true, true, false),
 	}
 }
 
@@ -311,6 +338,7 @@ func GoDebugEvalVariableTool() protocol.Tool {
 			},
 			Required: []string{"name"},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("Evaluate Variable", false),
 	}
 }
 
@@ -350,6 +378,7 @@ func GoDebugCloseTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.MutatingAnnotations("Close Debugger", true, true, false),
 	}
 }
 
@@ -357,7 +386,7 @@ func HandleGoDebugClose(params any) (any, error) {
 	client := getDebugClient()
 	response, err := client.Close()
 	if err != nil {
-		return nil, err
+		return nil, debugError(err)
 	}
 	
 	// Reset the client for next session
@@ -377,6 +406,7 @@ func GoDebugGetOutputTool() protocol.Tool {
 			Type:       "object",
 			Properties: map[string]protocol.ToolProperty{},
 		},
+		Annotations: protocol.ReadOnlyAnnotations("Get Program Output", false),
 	}
 }
 
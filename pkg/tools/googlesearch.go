@@ -55,6 +55,30 @@ func GoogleSearchTool() protocol.Tool {
 			},
 			Required: []string{"query"},
 		},
+		OutputSchema: searchResultsOutputSchema("The search results, each with a title, url and description"),
+		Annotations:  protocol.ReadOnlyAnnotations("Google Search", true),
+	}
+}
+
+// searchResultsOutputSchema returns the output schema shared by the search tools
+func searchResultsOutputSchema(resultsDescription string) *protocol.OutputSchema {
+	return &protocol.OutputSchema{
+		Type: "object",
+		Properties: map[string]protocol.ToolProperty{
+			"results": {
+				Type:        "array",
+				Description: resultsDescription,
+			},
+			"query": {
+				Type:        "string",
+				Description: "The query that was searched for",
+			},
+			"count": {
+				Type:        "integer",
+				Description: "The number of results returned",
+			},
+		},
+		Required: []string{"results", "query", "count"},
 	}
 }
 
@@ -100,6 +124,144 @@ func HandleGoogleSearchTool(params any) (any, error) {
 	}, nil
 }
 
+// ImageResult represents a single image search result
+type ImageResult struct {
+	Title           string `json:"title"`
+	URL             string `json:"url"`
+	ContentType     string `json:"contentType,omitempty"`
+	Width           int    `json:"width,omitempty"`
+	Height          int    `json:"height,omitempty"`
+	ByteSize        int    `json:"byteSize,omitempty"`
+	ThumbnailURL    string `json:"thumbnailUrl,omitempty"`
+	ThumbnailWidth  int    `json:"thumbnailWidth,omitempty"`
+	ThumbnailHeight int    `json:"thumbnailHeight,omitempty"`
+	SourcePage      string `json:"sourcePage,omitempty"`
+}
+
+// ImageSearchTool returns the general image search tool definition
+func ImageSearchTool() protocol.Tool {
+	return protocol.Tool{
+		Name: "image_search",
+		Description: `
+		Performs an internet (google) image search for the given text and returns the top 'num' images.
+		Unlike get_image this is not limited to Wikipedia and does not download anything.
+		For each of the 'num' results the following information is returned:
+		- title: The title of the image
+		- url: The URL of the full size image
+		- contentType, width, height, byteSize: The format and dimensions of the image
+		- thumbnailUrl, thumbnailWidth, thumbnailHeight: A smaller preview of the image
+		- sourcePage: The web page on which the image was found
+		This tool should be used when the user wants to choose between several images of something
+		`,
+		InputSchema: protocol.InputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"query": {
+					Type:        "string",
+					Description: "The search term for example 'Ozric Tentacles live' ",
+				},
+				"num": {
+					Type:        "integer",
+					Description: "The number of images to return, defaults to 5 (maximum 10)",
+				},
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: searchResultsOutputSchema("The image results, each with a url, thumbnailUrl, dimensions and sourcePage"),
+		Annotations:  protocol.ReadOnlyAnnotations("Image Search", true),
+	}
+}
+
+// HandleImageSearchTool handles the image search tool invocation
+func HandleImageSearchTool(params any) (any, error) {
+	logger.Info("Handling image search tool invocation")
+
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters format")
+	}
+
+	query, ok := paramsMap["query"].(string)
+	if !ok {
+		return nil, fmt.Errorf("query parameter is required and must be a string")
+	}
+
+	// Get number of results (default to 5)
+	numResults := 5
+	if numFloat, ok := paramsMap["num"].(float64); ok {
+		numResults = int(numFloat)
+	}
+	if numResults <= 0 || numResults > 10 {
+		numResults = 5 // Reset to default if invalid
+	}
+
+	results, err := ImageSearch(query, numResults)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"results": results,
+		"query":   query,
+		"count":   len(results),
+	}, nil
+}
+
+// ImageSearch performs a Google image search using the Custom Search API and returns the top n images
+func ImageSearch(query string, n int) ([]ImageResult, error) {
+	if n <= 0 {
+		n = 5
+	}
+	// the Custom Search API rejects requests for more than 10 results
+	n = min(n, 10)
+
+	body, err := customSearch(query, n, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the JSON response
+	var searchResponse struct {
+		Items []struct {
+			Title string `json:"title"`
+			Link  string `json:"link"`
+			Mime  string `json:"mime"`
+			Image struct {
+				ContextLink     string `json:"contextLink"`
+				Height          int    `json:"height"`
+				Width           int    `json:"width"`
+				ByteSize        int    `json:"byteSize"`
+				ThumbnailLink   string `json:"thumbnailLink"`
+				ThumbnailHeight int    `json:"thumbnailHeight"`
+				ThumbnailWidth  int    `json:"thumbnailWidth"`
+			} `json:"image"`
+		} `json:"items"`
+	}
+
+	err = json.Unmarshal(body, &searchResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	var results []ImageResult
+	for _, item := range searchResponse.Items {
+		results = append(results, ImageResult{
+			Title:           item.Title,
+			URL:             item.Link,
+			ContentType:     item.Mime,
+			Width:           item.Image.Width,
+			Height:          item.Image.Height,
+			ByteSize:        item.Image.ByteSize,
+			ThumbnailURL:    item.Image.ThumbnailLink,
+			ThumbnailWidth:  item.Image.ThumbnailWidth,
+			ThumbnailHeight: item.Image.ThumbnailHeight,
+			SourcePage:      item.Image.ContextLink,
+		})
+	}
+
+	return results, nil
+}
+
 // googleSearch performs a Google search using the Custom Search API and returns the top results
 func GoogleSearch(query string, numResults int, images bool) ([]SearchResult, error) {
 	// These would typically be stored in environment variables or configuration
@@ -108,6 +270,42 @@ func GoogleSearch(query string, numResults int, images bool) ([]SearchResult, er
 		numResults = 5 // Default to 5 results if not specified or invalid
 	}
 
+	body, err := customSearch(query, numResults, images)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the JSON response
+	var searchResponse struct {
+		Items []struct {
+			Title       string `json:"title"`
+			Link        string `json:"link"`
+			Snippet     string `json:"snippet"`
+			DisplayLink string `json:"displayLink"`
+		} `json:"items"`
+	}
+
+	err = json.Unmarshal(body, &searchResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Convert the API response to our SearchResult format
+	var results []SearchResult
+	for _, item := range searchResponse.Items {
+		results = append(results, SearchResult{
+			Title:       item.Title,
+			URL:         item.Link,
+			Description: item.Snippet,
+		})
+	}
+
+	// Return the results, which may be an empty array if no results were found
+	return results, nil
+}
+
+// customSearch calls the Google Custom Search API and returns the raw JSON response body
+func customSearch(query string, numResults int, images bool) ([]byte, error) {
 	// Google Custom Search API endpoint
 	baseURL := "https://www.googleapis.com/customsearch/v1"
 
@@ -144,14 +342,17 @@ func GoogleSearch(query string, numResults int, images bool) ([]SearchResult, er
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to search API: %w", err)
+		return nil, protocol.NewDataError(fmt.Errorf("failed to connect to search API: %w", err), map[string]any{"url": transport.RedactURL(searchURL)})
 	}
 	defer resp.Body.Close()
 
 	// Check if the response status code is not 200 OK
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("search API returned error status %d: %s", resp.StatusCode, string(body))
+		return nil, protocol.NewDataError(fmt.Errorf("search API returned error status %d: %s", resp.StatusCode, string(body)), map[string]any{
+			"url":    transport.RedactURL(searchURL),
+			"status": resp.StatusCode,
+		})
 	}
 
 	// Read the response body
@@ -159,32 +360,5 @@ func GoogleSearch(query string, numResults int, images bool) ([]SearchResult, er
 	if err != nil {
 		return nil, fmt.Errorf("failed to read search response: %w", err)
 	}
-
-	// Parse the JSON response
-	var searchResponse struct {
-		Items []struct {
-			Title       string `json:"title"`
-			Link        string `json:"link"`
-			Snippet     string `json:"snippet"`
-			DisplayLink string `json:"displayLink"`
-		} `json:"items"`
-	}
-
-	err = json.Unmarshal(body, &searchResponse)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse API response: %w", err)
-	}
-
-	// Convert the API response to our SearchResult format
-	var results []SearchResult
-	for _, item := range searchResponse.Items {
-		results = append(results, SearchResult{
-			Title:       item.Title,
-			URL:         item.Link,
-			Description: item.Snippet,
-		})
-	}
-
-	// Return the results, which may be an empty array if no results were found
-	return results, nil
+	return body, nil
 }
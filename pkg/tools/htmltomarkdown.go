@@ -35,6 +35,17 @@ func HTMLToMarkdownTool() protocol.Tool {
 			},
 			Required: []string{"url"},
 		},
+		OutputSchema: &protocol.OutputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"markdown": {Type: "string", Description: "The page content as markdown"},
+				"url":      {Type: "string", Description: "The URL that was converted"},
+				"title":    {Type: "string", Description: "The title of the page"},
+				"domain":   {Type: "string", Description: "The domain of the page"},
+			},
+			Required: []string{"markdown", "url"},
+		},
+		Annotations: protocol.ReadOnlyAnnotations("Web Page to Markdown", true),
 	}
 }
 
@@ -62,6 +73,17 @@ func HTMLToMarkdownFileTool() protocol.Tool {
 			},
 			Required: []string{"url"},
 		},
+		OutputSchema: &protocol.OutputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"filepath": {Type: "string", Description: "The local file the markdown was written to"},
+				"url":      {Type: "string", Description: "The URL that was converted"},
+				"title":    {Type: "string", Description: "The title of the page"},
+				"domain":   {Type: "string", Description: "The domain of the page"},
+			},
+			Required: []string{"filepath", "url"},
+		},
+		Annotations: protocol.MutatingAnnotations("Web Page to Markdown File", true, true, true),
 	}
 }
 
@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// HTML_ROOT_ENV names the directory local html files are confined to,
+// the working directory of the server is used if it isn't set
+const HTML_ROOT_ENV = "MCP_HTML_ROOT"
+
+// File extensions accepted as html without inspecting the content
+var htmlExtensions = map[string]bool{".html": true, ".htm": true, ".xhtml": true}
+
+func LocalHTMLToMarkdownTool() protocol.Tool {
+	return protocol.Tool{
+		Name: "local_html_2_markdown",
+		Description: `
+		Converts a local HTML file to Markdown format for comsumption by LLM clients.
+		Only files inside the configured root directory can be read or written.
+		If an output path is given the markdown is also written to that file.
+		This tool should be used when:
+		- The user asks for a Precis or summary of a saved web page or html report
+		- The user wants a local html file converted to markdown
+		`,
+		InputSchema: protocol.InputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"path": {
+					Type:        "string",
+					Description: "The path of the html file to convert, relative paths are resolved against the root directory",
+				},
+				"output_path": {
+					Type:        "string",
+					Description: "Optional path of a file in which to store the markdown",
+				},
+			},
+			Required: []string{"path"},
+		},
+		OutputSchema: &protocol.OutputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"markdown": {Type: "string", Description: "The file content as markdown"},
+				"path":     {Type: "string", Description: "The html file that was converted"},
+				"title":    {Type: "string", Description: "The title of the page"},
+				"filepath": {Type: "string", Description: "The local file the markdown was written to, if an output path was given"},
+			},
+			Required: []string{"markdown", "path"},
+		},
+		Annotations: protocol.MutatingAnnotations("Local HTML to Markdown", true, true, false),
+	}
+}
+
+// HandleLocalHTMLToMarkdown converts a local html file within the configured root to markdown
+func HandleLocalHTMLToMarkdown(params any) (any, error) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters format")
+	}
+	path, ok := paramsMap["path"].(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("no path was passed")
+	}
+	outputPath, _ := paramsMap["output_path"].(string)
+
+	root, err := HTMLRoot()
+	if err != nil {
+		return nil, err
+	}
+	return LocalHTMLToMarkdown(root, path, outputPath)
+}
+
+// HTMLRoot returns the directory local html files are confined to
+func HTMLRoot() (string, error) {
+	if root := strings.TrimSpace(os.Getenv(HTML_ROOT_ENV)); root != "" {
+		return root, nil
+	}
+	return os.Getwd()
+}
+
+// LocalHTMLToMarkdown converts the html file at path to markdown, writing it to outputPath
+// if that isn't empty. Both paths must be inside root once relative paths, '..' elements
+// and symbolic links have been resolved
+func LocalHTMLToMarkdown(root string, path string, outputPath string) (map[string]any, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root directory: %w", err)
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, fmt.Errorf("invalid root directory: %w", err)
+	}
+
+	source, err := resolveWithinRoot(root, path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read html file: %w", err)
+	}
+	if err := checkHTML(source, content); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Converting local HTML to markdown:", source)
+	markdown, err := htmltomarkdown.ConvertString(string(content))
+	if err != nil {
+		logger.Error("Failed to convert HTML to Markdown:", err)
+		return nil, err
+	}
+
+	ret := map[string]any{
+		"markdown": markdown,
+		"path":     source,
+		"title":    extractTitle(string(content)),
+	}
+	if strings.TrimSpace(outputPath) == "" {
+		return ret, nil
+	}
+
+	target, err := resolveWithinRoot(root, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(target, []byte(markdown), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write markdown file: %w", err)
+	}
+	logger.Info("Markdown saved to", target)
+	ret["filepath"] = target
+	return ret, nil
+}
+
+// resolveWithinRoot returns the absolute form of path, resolved against root if relative,
+// or an error if it lies outside root. Symbolic links are followed for as much of the
+// path as exists so a link can't be used to escape the root
+func resolveWithinRoot(root string, path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	path = filepath.Clean(path)
+
+	// resolve links in the deepest existing ancestor, the rest of the path doesn't exist yet
+	existing, rest := path, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	resolved = filepath.Join(resolved, rest)
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside the permitted root directory %s", path, root)
+	}
+	return resolved, nil
+}
+
+// checkHTML returns an error unless the file looks like html, either by its extension
+// or by its content. Binary content is rejected whatever the extension
+func checkHTML(path string, content []byte) error {
+	detected := http.DetectContentType(content)
+	if !strings.HasPrefix(detected, "text/") {
+		return fmt.Errorf("%s is not an HTML file (detected content type %s)", path, detected)
+	}
+	if !htmlExtensions[strings.ToLower(filepath.Ext(path))] && !strings.HasPrefix(detected, "text/html") {
+		return fmt.Errorf("%s is not an HTML file: expected a .html, .htm or .xhtml extension or HTML content", path)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/richard-senior/mcp/internal/logger"
 	"github.com/richard-senior/mcp/pkg/protocol"
@@ -37,9 +38,14 @@ func NewMemeTool() protocol.Tool {
 					Type:        "string",
 					Description: "The absolute filepath in which to store the resulting svg file. If omitted will default to the present working directory.",
 				},
+				"output_path": {
+					Type:        "string",
+					Description: "If given, the caption is drawn onto the image and the meme is saved as a PNG file at this path instead of as an svg file. The .png extension is added if necessary.",
+				},
 			},
 			Required: []string{"searchterm", "text"},
 		},
+		Annotations: protocol.MutatingAnnotations("Meme", true, false, true),
 	}
 }
 
@@ -68,6 +74,20 @@ func HandleMemeTool(params any) (any, error) {
 	if err != nil {
 		return nil, err
 	}
+	// render the caption into a png rather than creating an svg
+	if outputPath, ok := paramsMap["output_path"].(string); ok && strings.TrimSpace(outputPath) != "" {
+		img, err := DecodeMemeImage(bytes)
+		if err != nil {
+			return nil, err
+		}
+		location, err := SaveMemePNG(img, text, outputPath)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"location": location,
+		}, nil
+	}
 	svg, err := util.NewSVGFromRasterContent(bytes)
 	if err != nil {
 		return nil, err
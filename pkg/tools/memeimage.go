@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	_ "golang.org/x/image/webp"
+)
+
+// Limits on the size of the caption font in pixels
+const (
+	MEME_MIN_FONT_SIZE = 12
+	MEME_MAX_FONT_SIZE = 72
+)
+
+// DecodeMemeImage decodes jpeg, png, gif or webp image content for use as a meme template
+func DecodeMemeImage(content []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the meme image: %w", err)
+	}
+	return img, nil
+}
+
+// MemeCaptionBox returns the area of an image in which the caption is drawn,
+// the bottom quarter of the image (at least 60 pixels high) inside a margin
+func MemeCaptionBox(bounds image.Rectangle) image.Rectangle {
+	margin := min(20, bounds.Dx()/20, bounds.Dy()/20)
+	height := max(bounds.Dy()/4, 60)
+	if height > bounds.Dy()-2*margin {
+		height = bounds.Dy() - 2*margin
+	}
+	return image.Rect(
+		bounds.Min.X+margin,
+		bounds.Max.Y-margin-height,
+		bounds.Max.X-margin,
+		bounds.Max.Y-margin,
+	)
+}
+
+// RenderMemeCaption draws the text onto a copy of the image in white with a black outline.
+// The text is wrapped to the width of the caption box and the largest font size
+// at which it fits the box is used. An error is returned if it doesn't fit at any size
+func RenderMemeCaption(img image.Image, text string) (*image.RGBA, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("no caption text was given")
+	}
+	ttf, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load caption font: %w", err)
+	}
+
+	box := MemeCaptionBox(img.Bounds())
+	var face font.Face
+	var lines []string
+	var lineHeight int
+	// small images still try the smallest font rather than none at all
+	for size := max(MEME_MIN_FONT_SIZE, min(MEME_MAX_FONT_SIZE, img.Bounds().Dy()/8)); size >= MEME_MIN_FONT_SIZE; size -= 2 {
+		f, err := opentype.NewFace(ttf, &opentype.FaceOptions{Size: float64(size), DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create caption font face: %w", err)
+		}
+		wrapped, ok := wrapCaption(f, text, box.Dx())
+		height := int(float64(size) * 1.2) // 1.2 line spacing as in the svg meme
+		if ok && len(wrapped)*height <= box.Dy() {
+			face, lines, lineHeight = f, wrapped, height
+			break
+		}
+		f.Close()
+	}
+	if face == nil {
+		return nil, fmt.Errorf("caption text does not fit the %dx%d caption box, try shorter text", box.Dx(), box.Dy())
+	}
+	defer face.Close()
+	logger.Info("Rendering meme caption lines", len(lines))
+
+	ret := image.NewRGBA(img.Bounds())
+	draw.Draw(ret, ret.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	// outline thickness grows with the font so the text stays readable on any background
+	outline := max(1, lineHeight/15)
+	ascent := face.Metrics().Ascent.Ceil()
+	top := box.Max.Y - len(lines)*lineHeight
+	d := &font.Drawer{Dst: ret, Face: face}
+	for i, line := range lines {
+		x := box.Min.X + (box.Dx()-d.MeasureString(line).Ceil())/2
+		y := top + i*lineHeight + ascent
+
+		d.Src = image.NewUniform(color.Black)
+		for dx := -outline; dx <= outline; dx++ {
+			for dy := -outline; dy <= outline; dy++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				d.Dot = fixed.P(x+dx, y+dy)
+				d.DrawString(line)
+			}
+		}
+		d.Src = image.NewUniform(color.White)
+		d.Dot = fixed.P(x, y)
+		d.DrawString(line)
+	}
+	return ret, nil
+}
+
+// wrapCaption splits text into lines no wider than width when drawn with face.
+// Returns false if a single word is too wide to fit on a line
+func wrapCaption(face font.Face, text string, width int) ([]string, bool) {
+	lines := []string{}
+	current := ""
+	for _, word := range strings.Fields(text) {
+		if font.MeasureString(face, word).Ceil() > width {
+			return nil, false
+		}
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if font.MeasureString(face, candidate).Ceil() > width {
+			lines = append(lines, current)
+			current = word
+		} else {
+			current = candidate
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines, true
+}
+
+// SaveMemePNG renders the caption onto the image and writes it as a PNG.
+// The output path is given a .png extension (replacing any other extension) and its
+// directory is created if necessary. Returns the path the image was saved to
+func SaveMemePNG(img image.Image, text string, outputPath string) (string, error) {
+	outputPath = strings.TrimSpace(outputPath)
+	if outputPath == "" {
+		return "", fmt.Errorf("no output path was given")
+	}
+
+	// If the output path doesn't have an extension, add one
+	if !strings.Contains(filepath.Base(outputPath), ".") {
+		outputPath = outputPath + ".png"
+	} else {
+		// Replace the existing extension with the correct one
+		outputPath = strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".png"
+	}
+
+	rendered, err := RenderMemeCaption(img, text)
+	if err != nil {
+		return "", err
+	}
+
+	// Create the directory if it doesn't exist
+	dir := filepath.Dir(outputPath)
+	if dir != "." && dir != "/" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create meme file: %w", err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, rendered); err != nil {
+		return "", fmt.Errorf("failed to write meme to disk: %w", err)
+	}
+
+	logger.Info("Meme saved to", outputPath)
+	return outputPath, nil
+}
@@ -29,6 +29,7 @@ func NewOrchastrationTool() protocol.Tool {
 			},
 			Required: []string{"basepath", "prompt"},
 		},
+		Annotations: protocol.MutatingAnnotations("Orchestration Agent", true, false, true),
 	}
 }
 
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/prompts"
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+func PromptPreviewTool() protocol.Tool {
+	return protocol.Tool{
+		Name: "prompt_preview",
+		Description: `
+		Renders a stored prompt with sample argument values so that it can be checked before it is used.
+		Not every variable has to be supplied, any placeholders left unsubstituted are reported.
+		This tool should be used when:
+		- The user is writing or debugging a prompt
+		- The user asks what a prompt will look like with particular values
+		`,
+		InputSchema: protocol.InputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"name": {
+					Type:        "string",
+					Description: "The name (ID) of the prompt to preview ie. code-review",
+				},
+				"arguments": {
+					Type:        "object",
+					Description: "Sample values for the prompt's variables keyed by variable name ie. {\"language\": \"go\"}",
+				},
+			},
+			Required: []string{"name"},
+		},
+		OutputSchema: &protocol.OutputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"name":            {Type: "string", Description: "The name of the prompt"},
+				"content":         {Type: "string", Description: "The prompt with the sample arguments substituted"},
+				"unsubstituted":   {Type: "array", Description: "Placeholders left in the content because no value was supplied"},
+				"missingRequired": {Type: "array", Description: "Required variables for which no value was supplied"},
+			},
+			Required: []string{"name", "content", "unsubstituted", "missingRequired"},
+		},
+		Annotations: protocol.ReadOnlyAnnotations("Prompt Preview", false),
+	}
+}
+
+// HandlePromptPreview renders a prompt from the registry with the supplied sample arguments
+func HandlePromptPreview(params any) (any, error) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters format")
+	}
+	name, ok := paramsMap["name"].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("no prompt name was passed")
+	}
+
+	args := map[string]string{}
+	if raw, ok := paramsMap["arguments"]; ok && raw != nil {
+		argsMap, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("arguments must be an object of variable names to values")
+		}
+		for key, value := range argsMap {
+			if s, ok := value.(string); ok {
+				args[key] = s
+			} else {
+				args[key] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	prompt, err := prompts.GetGlobalRegistry().GetPrompt(name)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Previewing prompt", name)
+	return PreviewPrompt(*prompt, args), nil
+}
+
+// PreviewPrompt renders the prompt with the given arguments, reporting placeholders
+// left unsubstituted and required variables which weren't supplied
+func PreviewPrompt(prompt protocol.Prompt, args map[string]string) map[string]any {
+	content, unsubstituted := prompts.RenderPrompt(prompt, args)
+
+	missing := []string{}
+	for key, variable := range prompt.Variables {
+		if _, ok := args[key]; variable.Required && !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	return map[string]any{
+		"name":            prompt.ID,
+		"content":         content,
+		"unsubstituted":   unsubstituted,
+		"missingRequired": missing,
+	}
+}
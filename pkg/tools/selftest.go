@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// SelfTestCheck is a cheap probe of a single external dependency
+type SelfTestCheck struct {
+	Name string
+	// Required checks fail the self test, others are only reported
+	Required bool
+	Check    func() error
+}
+
+// SelfTestResult is the outcome of a single SelfTestCheck
+type SelfTestResult struct {
+	Name      string `json:"name"`
+	Required  bool   `json:"required"`
+	OK        bool   `json:"ok"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DefaultSelfTestChecks returns the checks for each external dependency used by the tools.
+// Each makes a single small request through the shared HTTP client, so is subject to its timeout
+func DefaultSelfTestChecks() []SelfTestCheck {
+	return []SelfTestCheck{
+		{
+			Name:     "google_search",
+			Required: true,
+			Check: func() error {
+				_, err := customSearch("test", 1, false)
+				return err
+			},
+		},
+		{
+			Name:     "wikipedia",
+			Required: true,
+			Check: func() error {
+				_, err := findWikipediaImageURL("Earth", 100)
+				return err
+			},
+		},
+	}
+}
+
+// RunSelfTest runs each check in turn, timing it
+func RunSelfTest(checks []SelfTestCheck) []SelfTestResult {
+	results := make([]SelfTestResult, 0, len(checks))
+	for _, check := range checks {
+		start := time.Now()
+		err := check.Check()
+		result := SelfTestResult{
+			Name:      check.Name,
+			Required:  check.Required,
+			OK:        err == nil,
+			LatencyMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			logger.Warn("Self test check failed", check.Name, err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// SelfTestPassed returns true if none of the required checks failed
+func SelfTestPassed(results []SelfTestResult) bool {
+	for _, result := range results {
+		if result.Required && !result.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteSelfTestReport writes the results as a plain text table, one dependency per line
+func WriteSelfTestReport(w io.Writer, results []SelfTestResult) {
+	for _, result := range results {
+		status := "OK"
+		if !result.OK {
+			status = "FAIL"
+			if !result.Required {
+				status = "WARN"
+			}
+		}
+		line := fmt.Sprintf("%-4s %-16s %6dms", status, result.Name, result.LatencyMs)
+		if result.Error != "" {
+			line += "  " + strings.ReplaceAll(result.Error, "\n", " ")
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// SelfTestTool returns the tool definition for the connectivity self test
+func SelfTestTool() protocol.Tool {
+	return protocol.Tool{
+		Name: "selftest",
+		Description: `
+		Checks that the external services used by the other tools (Google search and Wikipedia)
+		can be reached and that the configured credentials are accepted.
+		Each service is sent a single trivial request and reported as OK or failed with its latency.
+		This tool should be used when:
+		- Other tools are failing with connection or authentication errors
+		- The user asks whether the server is working
+		`,
+		InputSchema: protocol.InputSchema{
+			Type:       "object",
+			Properties: map[string]protocol.ToolProperty{},
+		},
+		OutputSchema: &protocol.OutputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"passed":  {Type: "boolean", Description: "True if every required dependency is reachable"},
+				"results": {Type: "array", Description: "The name, required flag, ok flag, latencyMs and any error of each dependency"},
+			},
+			Required: []string{"passed", "results"},
+		},
+		Annotations: protocol.ReadOnlyAnnotations("Self Test", true),
+	}
+}
+
+// HandleSelfTest is the handler function for the self test tool
+func HandleSelfTest(params any) (any, error) {
+	results := RunSelfTest(DefaultSelfTestChecks())
+	return map[string]any{
+		"passed":  SelfTestPassed(results),
+		"results": results,
+	}, nil
+}
@@ -73,6 +73,7 @@ func NewSvgTool() protocol.Tool {
 			},
 			Required: []string{"command"},
 		},
+		Annotations: protocol.MutatingAnnotations("SVG Tool", true, false, true),
 	}
 }
 
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/util"
+)
+
+// SvgPathInfo describes the geometry of a parsed SVG path
+type SvgPathInfo struct {
+	Valid        bool    `json:"valid"`
+	Error        string  `json:"error,omitempty"`
+	ID           string  `json:"id,omitempty"`
+	CommandCount int     `json:"commandCount"`
+	Subpaths     int     `json:"subpaths"`
+	Closed       bool    `json:"closed"`
+	PointCount   int     `json:"pointCount"`
+	Length       float64 `json:"length"`
+	MinX         float64 `json:"minX"`
+	MinY         float64 `json:"minY"`
+	MaxX         float64 `json:"maxX"`
+	MaxY         float64 `json:"maxY"`
+}
+
+// SvgPathInfoTool returns the tool definition for checking an SVG path
+func SvgPathInfoTool() protocol.Tool {
+	return protocol.Tool{
+		Name: "svg_path_info",
+		Description: `
+		Parses an SVG path and reports its geometry, so that a path can be checked before it is used
+		to generate GCode or otherwise processed.
+		Accepts either the d attribute of a path (e.g. "M 0,0 L 10,0 V 10 Z") or a whole <path ... /> tag.
+		Returns whether the path is valid and, if it is, the number of commands and subpaths, whether it is closed,
+		its total length and its bounding box. If it isn't valid the error names the offending command or position.
+		Supported commands are M, L, H, V, Q, A and Z in both absolute and relative forms.
+		`,
+		InputSchema: protocol.InputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"path": {
+					Type:        "string",
+					Description: "The d attribute of an SVG path, or a complete <path> tag",
+				},
+				"max_distance": {
+					Type:        "number",
+					Description: "The maximum distance between the points the path is divided into when measuring it, defaults to 0.5",
+				},
+			},
+			Required: []string{"path"},
+		},
+		OutputSchema: &protocol.OutputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"valid":        {Type: "boolean", Description: "Whether the path could be parsed and measured"},
+				"error":        {Type: "string", Description: "Why the path is invalid"},
+				"id":           {Type: "string", Description: "The id of the path tag, if any"},
+				"commandCount": {Type: "integer", Description: "The number of path commands, with repeated parameter sets counted separately"},
+				"subpaths":     {Type: "integer", Description: "The number of move commands starting a subpath"},
+				"closed":       {Type: "boolean", Description: "Whether the path finishes where it started"},
+				"pointCount":   {Type: "integer", Description: "The number of points the path was divided into"},
+				"length":       {Type: "number", Description: "The total length of the path"},
+				"minX":         {Type: "number", Description: "The left of the bounding box"},
+				"minY":         {Type: "number", Description: "The top of the bounding box"},
+				"maxX":         {Type: "number", Description: "The right of the bounding box"},
+				"maxY":         {Type: "number", Description: "The bottom of the bounding box"},
+			},
+			Required: []string{"valid", "commandCount"},
+		},
+		Annotations: protocol.ReadOnlyAnnotations("SVG Path Info", false),
+	}
+}
+
+// HandleSvgPathInfo is the handler function for the svg path info tool
+func HandleSvgPathInfo(params any) (any, error) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters format")
+	}
+	path, ok := paramsMap["path"].(string)
+	if !ok || strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("path parameter is required")
+	}
+	maxDistance := util.DefaultMaxPointDistance
+	if v, ok := paramsMap["max_distance"].(float64); ok {
+		if v <= 0 {
+			return nil, fmt.Errorf("max_distance must be greater than zero")
+		}
+		maxDistance = v
+	}
+	return GetSvgPathInfo(path, maxDistance), nil
+}
+
+// GetSvgPathInfo parses and pointalises a path given as either a d attribute or a whole path tag.
+// Problems with the path are reported in the returned info rather than as an error
+func GetSvgPathInfo(path string, maxDistance float64) *SvgPathInfo {
+	path = strings.TrimSpace(path)
+	info := &SvgPathInfo{}
+
+	var p *util.Path
+	if strings.HasPrefix(path, "<") {
+		var err error
+		p, err = util.NewPathFromSvgTag(path)
+		if err != nil {
+			info.Error = err.Error()
+			return info
+		}
+	} else {
+		p = &util.Path{CommandsStr: path}
+		if err := p.ParsePathCommands(); err != nil {
+			info.Error = err.Error()
+			return info
+		}
+	}
+	info.ID = p.ID
+	info.CommandCount = len(p.Commands)
+	for _, cmd := range p.Commands {
+		if cmd.Letter == "M" || cmd.Letter == "m" {
+			info.Subpaths++
+		}
+	}
+
+	if err := p.Pointalise(maxDistance); err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	minX, minY, maxX, maxY, err := p.BoundingBox()
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+
+	last := p.Commands[len(p.Commands)-1].Letter
+	first, end := p.Points[0], p.Points[len(p.Points)-1]
+	info.Closed = last == "Z" || last == "z" || (len(p.Points) > 2 && first.X == end.X && first.Y == end.Y)
+	info.PointCount = len(p.Points)
+	info.Length = p.Length()
+	info.MinX, info.MinY, info.MaxX, info.MaxY = minX, minY, maxX, maxY
+	info.Valid = true
+	logger.Info("Measured svg path", info.CommandCount, "commands", info.Length, "long")
+	return info
+}
@@ -20,8 +20,15 @@ const (
 	THOUGHTS_DATA_FILE = "thoughts.json"
 	// Auto-save interval in seconds
 	AUTO_SAVE_INTERVAL = 30
+	// Comma separated list of allowed thought categories, overrides DEFAULT_THOUGHT_CATEGORIES
+	THOUGHT_CATEGORIES_ENV = "MCP_THOUGHT_CATEGORIES"
+	// Category given to thoughts which don't specify one
+	UNCATEGORISED = "uncategorised"
 )
 
+// The thought categories allowed when MCP_THOUGHT_CATEGORIES isn't set
+var DEFAULT_THOUGHT_CATEGORIES = []string{"hypothesis", "decision", "todo", "question", "observation", "conclusion"}
+
 // ThoughtData represents a single thought in the sequential thinking process
 type ThoughtData struct {
 	Thought           string    `json:"thought"`
@@ -34,6 +41,8 @@ type ThoughtData struct {
 	BranchID          string    `json:"branchId,omitempty"`
 	NeedsMoreThoughts bool      `json:"needsMoreThoughts,omitempty"`
 	Outcomes          string    `json:"outcomes,omitempty"`
+	Category          string    `json:"category,omitempty"`
+	SessionID         string    `json:"sessionId,omitempty"`
 	Timestamp         time.Time `json:"timestamp"`
 }
 
@@ -172,6 +181,14 @@ This tool should be used when:
 					Type:        "string",
 					Description: "Things learned from the previous thought, or thoughts. If there are no outcomes then just pass a blank string.",
 				},
+				"category": {
+					Type:        "string",
+					Description: "Optional category for the thought ie. " + strings.Join(ThoughtCategories(), ", "),
+				},
+				"sessionId": {
+					Type:        "string",
+					Description: "Optional identifier grouping the thoughts of a single session so that they can be exported together",
+				},
 				"pwd": {
 					Type:        "string",
 					Description: "the present working directory of the client",
@@ -179,6 +196,7 @@ This tool should be used when:
 			},
 			Required: []string{"pwd", "thought", "nextThoughtNeeded", "thoughtNumber", "totalThoughts", "outcomes"},
 		},
+		Annotations: protocol.MutatingAnnotations("Thoughts", false, false, false),
 	}
 }
 
@@ -318,9 +336,24 @@ func (st *SequentialThinking) saveToFile() {
 	logger.Info("Saved thoughts data to %s", st.dataFile)
 }
 
-// GetThinkingInstance creates a new instance of SequentialThinking for the given pwd
+var (
+	thinkingInstances = map[string]*SequentialThinking{}
+	thinkingMutex     sync.Mutex
+)
+
+// GetThinkingInstance returns the SequentialThinking for the given pwd, creating it on first use.
+// There is one instance per thoughts file so every tool sees the same live thoughts
 func GetThinkingInstance(pwd string) *SequentialThinking {
-	return NewSequentialThinking(pwd)
+	dataFile := getThoughtsFilePath(pwd)
+
+	thinkingMutex.Lock()
+	defer thinkingMutex.Unlock()
+	if st, ok := thinkingInstances[dataFile]; ok {
+		return st
+	}
+	st := NewSequentialThinking(pwd)
+	thinkingInstances[dataFile] = st
+	return st
 }
 
 // ValidateThoughtData validates the input data
@@ -378,9 +411,50 @@ func (st *SequentialThinking) ValidateThoughtData(data map[string]interface{}) (
 		result.Outcomes = outcomes
 	}
 
+	if category, ok := data["category"].(string); ok && category != "" {
+		category, err := ValidateThoughtCategory(category)
+		if err != nil {
+			return ThoughtData{}, err
+		}
+		result.Category = category
+	}
+
+	if sessionID, ok := data["sessionId"].(string); ok {
+		result.SessionID = sessionID
+	}
+
 	return result, nil
 }
 
+// ThoughtCategories returns the allowed thought categories, read from MCP_THOUGHT_CATEGORIES
+// as a comma separated list or DEFAULT_THOUGHT_CATEGORIES if that isn't set
+func ThoughtCategories() []string {
+	value := strings.TrimSpace(os.Getenv(THOUGHT_CATEGORIES_ENV))
+	if value == "" {
+		return DEFAULT_THOUGHT_CATEGORIES
+	}
+	categories := []string{}
+	for _, c := range strings.Split(value, ",") {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			categories = append(categories, c)
+		}
+	}
+	return categories
+}
+
+// ValidateThoughtCategory checks the category is one of the allowed categories
+// and returns it normalised to lower case
+func ValidateThoughtCategory(category string) (string, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	allowed := ThoughtCategories()
+	for _, c := range allowed {
+		if c == category {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("invalid category '%s': must be one of %s", category, strings.Join(allowed, ", "))
+}
+
 // FormatThought formats a thought for display
 func (st *SequentialThinking) FormatThought(td ThoughtData) string {
 	var prefix, context string
@@ -444,6 +518,11 @@ func (st *SequentialThinking) ProcessThought(input map[string]interface{}) (inte
 		st.Branches[validatedInput.BranchID] = append(st.Branches[validatedInput.BranchID], validatedInput)
 	}
 
+	// Handle session if applicable
+	if validatedInput.SessionID != "" {
+		st.Sessions[validatedInput.SessionID] = append(st.Sessions[validatedInput.SessionID], validatedInput)
+	}
+
 	// Format and log the thought
 	formattedThought := st.FormatThought(validatedInput)
 	logger.Info(formattedThought)
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// ThoughtsExportTool returns the tool definition for exporting thoughts as markdown
+func ThoughtsExportTool() protocol.Tool {
+	return protocol.Tool{
+		Name: "thoughts_export",
+		Description: `
+		Exports the thoughts recorded by the thoughts tool as markdown, grouped by category with timestamps.
+		If a sessionId is given only the thoughts recorded in that session are exported,
+		otherwise the entire thought history is exported.
+		This tool should be used when:
+		- The user asks for a summary or record of previous thinking
+		- The user wants to review the hypotheses, decisions or todos from a session
+		`,
+		InputSchema: protocol.InputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"sessionId": {
+					Type:        "string",
+					Description: "The session whose thoughts should be exported",
+				},
+				"pwd": {
+					Type:        "string",
+					Description: "the present working directory of the client",
+				},
+			},
+			Required: []string{"pwd"},
+		},
+		OutputSchema: &protocol.OutputSchema{
+			Type: "object",
+			Properties: map[string]protocol.ToolProperty{
+				"markdown": {Type: "string", Description: "The thoughts rendered as markdown"},
+				"count":    {Type: "integer", Description: "The number of thoughts exported"},
+			},
+			Required: []string{"markdown", "count"},
+		},
+		Annotations: protocol.ReadOnlyAnnotations("Thoughts Export", false),
+	}
+}
+
+// HandleThoughtsExport is the handler function for the thoughts export tool
+func HandleThoughtsExport(params any) (any, error) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters format")
+	}
+	pwd, _ := paramsMap["pwd"].(string)
+	sessionID, _ := paramsMap["sessionId"].(string)
+
+	// export the live thoughts, which may not have been saved yet
+	st := GetThinkingInstance(pwd)
+
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	title := "Thoughts"
+	thoughts := st.ThoughtHistory
+	if sessionID != "" {
+		var exists bool
+		thoughts, exists = st.Sessions[sessionID]
+		if !exists {
+			return nil, fmt.Errorf("no thoughts found for session: %s", sessionID)
+		}
+		title = fmt.Sprintf("Thoughts for session %s", sessionID)
+	}
+
+	logger.Info("Exporting thoughts", len(thoughts))
+	return map[string]any{
+		"markdown": RenderThoughtsMarkdown(title, thoughts),
+		"count":    len(thoughts),
+	}, nil
+}
+
+// RenderThoughtsMarkdown renders thoughts as markdown under the given title.
+// Thoughts are grouped by category, in the order the categories are configured, followed
+// by any categories no longer configured and then uncategorised thoughts.
+// Within a category thoughts are listed in timestamp order
+func RenderThoughtsMarkdown(title string, thoughts []ThoughtData) string {
+	groups := map[string][]ThoughtData{}
+	for _, td := range thoughts {
+		category := td.Category
+		if category == "" {
+			category = UNCATEGORISED
+		}
+		groups[category] = append(groups[category], td)
+	}
+
+	// configured categories first, then anything else found in the stored thoughts
+	order := []string{}
+	seen := map[string]bool{UNCATEGORISED: true}
+	for _, c := range ThoughtCategories() {
+		if _, ok := groups[c]; ok && !seen[c] {
+			order = append(order, c)
+			seen[c] = true
+		}
+	}
+	others := []string{}
+	for c := range groups {
+		if !seen[c] {
+			others = append(others, c)
+		}
+	}
+	sort.Strings(others)
+	order = append(order, others...)
+	if _, ok := groups[UNCATEGORISED]; ok {
+		order = append(order, UNCATEGORISED)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# " + title + "\n")
+	if len(thoughts) == 0 {
+		sb.WriteString("\nNo thoughts have been recorded.\n")
+		return sb.String()
+	}
+	for _, category := range order {
+		group := groups[category]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Timestamp.Before(group[j].Timestamp)
+		})
+		sb.WriteString("\n## " + strings.ToUpper(category[:1]) + category[1:] + "\n\n")
+		for _, td := range group {
+			sb.WriteString(fmt.Sprintf("- **%s** (thought %d/%d", td.Timestamp.Format("2006-01-02 15:04:05"), td.ThoughtNumber, td.TotalThoughts))
+			if td.IsRevision {
+				sb.WriteString(fmt.Sprintf(", revises %d", td.RevisesThought))
+			}
+			if td.BranchID != "" {
+				sb.WriteString(", branch " + td.BranchID)
+			}
+			sb.WriteString(") " + td.Thought + "\n")
+			if td.Outcomes != "" {
+				sb.WriteString("  - Outcomes: " + td.Outcomes + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
@@ -1,21 +1,31 @@
 package tools
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/richard-senior/mcp/internal/logger"
 	"github.com/richard-senior/mcp/pkg/protocol"
 	"github.com/richard-senior/mcp/pkg/transport"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
+// WikipediaRetryPolicy is how often a search term variation is retried when Wikipedia can't be reached.
+// A variation with no image isn't retried, the next variation is tried instead
+var WikipediaRetryPolicy = transport.DefaultRetryPolicy
+
 // WikipediaImageTool returns the Wikipedia image search tool definition
 func WikipediaImageTool() protocol.Tool {
 	return protocol.Tool{
@@ -42,9 +52,22 @@ func WikipediaImageTool() protocol.Tool {
 					Type:        "integer",
 					Description: "The image width of the image to be downloaded, default is 500",
 				},
+				"output_mode": {
+					Type: "string",
+					Description: `
+						How the image should be returned. One of:
+						"file" (default) saves the image to disk and returns its location,
+						"bytes" returns the image as an embedded binary resource,
+						"base64" returns the image as a base64 encoded string,
+						"datauri" returns the image as a data URI (data:image/png;base64,...),
+						"url" returns the resolved image URL without downloading it.
+						The content type is always included.
+					`,
+				},
 			},
 			Required: []string{"query"},
 		},
+		Annotations: protocol.MutatingAnnotations("Get Image", true, true, true),
 	}
 }
 
@@ -79,12 +102,106 @@ func HandleWikipediaImageTool(params any) (any, error) {
 		}
 	}
 
-	// Save the image
-	ret, err := SaveWikipediaImage(query, imageSize, outputPath)
+	// Get the output mode (default to saving a file)
+	outputMode := "file"
+	if modeParam, ok := paramsMap["output_mode"].(string); ok && strings.TrimSpace(modeParam) != "" {
+		outputMode = strings.ToLower(strings.TrimSpace(modeParam))
+	}
+
+	switch outputMode {
+	case "file":
+		// Save the image
+		ret, err := SaveWikipediaImage(query, imageSize, outputPath)
+		if err != nil {
+			return nil, err
+		}
+		return ret, nil
+	case "url":
+		imageURL, contentType, err := WikipediaImageURLSearch(query, imageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image url: %w", err)
+		}
+		return map[string]any{
+			"url":         imageURL,
+			"contentType": contentType,
+		}, nil
+	case "bytes", "base64", "datauri":
+		imageData, contentType, err := WikipediaImageSearch(query, imageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image: %w", err)
+		}
+		return WikipediaImageResult(query, imageData, contentType, outputMode)
+	default:
+		return nil, protocol.NewDataError(fmt.Errorf("invalid output_mode '%s', must be one of file, bytes, base64, datauri or url", outputMode), map[string]any{
+			"parameter": "output_mode",
+			"value":     outputMode,
+			"allowed":   []string{"file", "bytes", "base64", "datauri", "url"},
+		})
+	}
+}
+
+// WikipediaImageResult shapes downloaded image bytes for the bytes, base64 and datauri output modes.
+// The bytes mode embeds the image in the tool result as a binary resource so that clients receive it
+// as an image rather than as a string, the other modes return the encoded image in a map
+func WikipediaImageResult(query string, imageData []byte, contentType string, outputMode string) (any, error) {
+	contentType = normaliseImageContentType(contentType, imageData)
+	encoded := base64.StdEncoding.EncodeToString(imageData)
+	switch outputMode {
+	case "bytes":
+		return &protocol.ToolResult{
+			Content: []protocol.ToolContent{{
+				Type: "resource",
+				Resource: &protocol.EmbeddedResource{
+					URI:      "wikipedia-image:" + url.PathEscape(strings.TrimSpace(query)),
+					MimeType: contentType,
+					Blob:     encoded,
+				},
+			}},
+		}, nil
+	case "base64":
+		return map[string]any{
+			"contentType": contentType,
+			"size":        len(imageData),
+			"data":        encoded,
+		}, nil
+	case "datauri":
+		return map[string]any{
+			"contentType": contentType,
+			"size":        len(imageData),
+			"data":        "data:" + contentType + ";base64," + encoded,
+		}, nil
+	default:
+		return nil, fmt.Errorf("output_mode '%s' doesn't return image data", outputMode)
+	}
+}
+
+// normaliseImageContentType strips any parameters from the given content type
+// and falls back to sniffing the image bytes when the server didn't supply a usable one
+func normaliseImageContentType(contentType string, data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && strings.HasPrefix(mediaType, "image/") {
+		return mediaType
+	}
+	if len(data) > 0 {
+		detected := http.DetectContentType(data)
+		if strings.HasPrefix(detected, "image/") {
+			return detected
+		}
+	}
+	return "application/octet-stream"
+}
+
+// imageContentTypeFromURL guesses the content type of an image from the extension of its URL
+func imageContentTypeFromURL(imageURL string) string {
+	u, err := url.Parse(imageURL)
 	if err != nil {
-		return nil, err
+		return "application/octet-stream"
+	}
+	contentType := mime.TypeByExtension(strings.ToLower(path.Ext(u.Path)))
+	if contentType == "" {
+		return "application/octet-stream"
 	}
-	return ret, nil
+	return normaliseImageContentType(contentType, nil)
 }
 
 // wikipediaImageSearch searches for an image on Wikipedia and returns the image bytes if found
@@ -97,30 +214,15 @@ func WikipediaImageSearch(query string, imageSize int) ([]byte, string, error) {
 	// Trim leading and trailing spaces from the query
 	query = strings.TrimSpace(query)
 
-	// Create an array of search term variations to try
-	variations := []string{
-		query,                                                // Original query
-		strings.ToLower(query),                               // Lowercase
-		strings.ReplaceAll(query, " ", "_"),                  // Replace spaces with underscores
-		strings.ReplaceAll(query, " ", "-"),                  // Replace spaces with hyphens
-		strings.Title(strings.ToLower(query)),                // Title case
-		strings.ReplaceAll(strings.ToLower(query), " ", "_"), // Lowercase with underscores
-		strings.ReplaceAll(strings.ToLower(query), " ", "-"), // Lowercase with hyphens
-	}
-
-	// Remove duplicates from variations
-	uniqueVariations := []string{}
-	seen := make(map[string]bool)
-	for _, variation := range variations {
-		if !seen[variation] {
-			seen[variation] = true
-			uniqueVariations = append(uniqueVariations, variation)
-		}
-	}
-
-	// Try each variation until we find an image
-	for _, searchTerm := range uniqueVariations {
-		imageData, contentType, err := tryWikipediaImageSearch(searchTerm, imageSize)
+	// Try each variation until we find an image, retrying those which fail with a network error
+	for _, searchTerm := range WikipediaSearchVariations(query) {
+		var imageData []byte
+		var contentType string
+		err := WikipediaRetryPolicy.Do(func() error {
+			var err error
+			imageData, contentType, err = tryWikipediaImageSearch(searchTerm, imageSize)
+			return err
+		})
 		if err == nil {
 			// Success! Return the image data
 			return imageData, contentType, nil
@@ -150,8 +252,108 @@ func WikipediaImageSearch(query string, imageSize int) ([]byte, string, error) {
 	return nil, "", fmt.Errorf("no image found for any variation of query: %s", query)
 }
 
+// WikipediaImageURLSearch resolves the URL of an image for the query without downloading it.
+// Returns the image URL and the content type implied by its extension
+func WikipediaImageURLSearch(query string, imageSize int) (string, string, error) {
+	// Default image size if not specified or invalid
+	if imageSize <= 0 {
+		imageSize = 500
+	}
+
+	// Trim leading and trailing spaces from the query
+	query = strings.TrimSpace(query)
+
+	for _, searchTerm := range WikipediaSearchVariations(query) {
+		var imageURL string
+		err := WikipediaRetryPolicy.Do(func() error {
+			var err error
+			imageURL, err = findWikipediaImageURL(searchTerm, imageSize)
+			return err
+		})
+		if err == nil {
+			return imageURL, imageContentTypeFromURL(imageURL), nil
+		}
+		logger.Info("Search failed for variation:", searchTerm, "- trying next variation")
+	}
+
+	logger.Info("Wikipedia returned nothing.. Calling Google Image Search")
+	ret, err := GoogleSearch(query, 1, true)
+	if err != nil {
+		return "", "", fmt.Errorf("no image found for any variation of query, and google search failed: %w", err)
+	}
+	for _, i := range ret {
+		if i.URL != "" {
+			return i.URL, imageContentTypeFromURL(i.URL), nil
+		}
+	}
+
+	return "", "", fmt.Errorf("no image found for any variation of query: %s", query)
+}
+
+// WikipediaSearchVariations returns the de-duplicated list of search terms to try for a query
+func WikipediaSearchVariations(query string) []string {
+	// Title case using unicode aware word breaking, casers are not safe for concurrent use
+	titleCaser := cases.Title(language.English)
+	titleCase := titleCaser.String(strings.ToLower(query))
+
+	// Create an array of search term variations to try
+	variations := []string{
+		query,                                 // Original query
+		strings.ToLower(query),                // Lowercase
+		strings.ReplaceAll(query, " ", "_"),   // Replace spaces with underscores
+		strings.ReplaceAll(query, " ", "-"),   // Replace spaces with hyphens
+		capitaliseAfterApostrophes(titleCase), // Title case for names such as O'Brien
+		titleCase,                             // Title case for words such as Don't
+		strings.ReplaceAll(strings.ToLower(query), " ", "_"), // Lowercase with underscores
+		strings.ReplaceAll(strings.ToLower(query), " ", "-"), // Lowercase with hyphens
+	}
+
+	// Remove duplicates from variations
+	uniqueVariations := []string{}
+	seen := make(map[string]bool)
+	for _, variation := range variations {
+		if !seen[variation] {
+			seen[variation] = true
+			uniqueVariations = append(uniqueVariations, variation)
+		}
+	}
+	return uniqueVariations
+}
+
+// capitaliseAfterApostrophes upper cases any letter following an apostrophe within a word,
+// which the title caser leaves lower case, e.g. O'brien becomes O'Brien
+func capitaliseAfterApostrophes(s string) string {
+	runes := []rune(s)
+	for i := 1; i+1 < len(runes); i++ {
+		if (runes[i] == '\'' || runes[i] == '’') && unicode.IsLetter(runes[i-1]) {
+			runes[i+1] = unicode.ToUpper(runes[i+1])
+		}
+	}
+	return string(runes)
+}
+
 // tryWikipediaImageSearch attempts to find an image on Wikipedia for a specific search term
 func tryWikipediaImageSearch(query string, imageSize int) ([]byte, string, error) {
+	imageURL, err := findWikipediaImageURL(query, imageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Now fetch the actual image
+	logger.Info("Found image for", query, "at URL:", imageURL)
+
+	imageData, contentType, err := transport.GetImage(imageURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+
+	logger.Info("Successfully retrieved image for", query, "with size:", len(imageData), "bytes")
+
+	return imageData, contentType, nil
+}
+
+// findWikipediaImageURL asks the Wikipedia API for the page image thumbnail URL of a specific search term
+func findWikipediaImageURL(query string, imageSize int) (string, error) {
 	// Wikipedia API endpoint for searching images
 	baseURL := "https://en.wikipedia.org/w/api.php"
 
@@ -168,13 +370,13 @@ func tryWikipediaImageSearch(query string, imageSize int) ([]byte, string, error
 	// Get a custom HTTP client with Zscaler support
 	client, err := transport.GetCustomHTTPClient()
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create HTTP client: %w", err)
+		return "", fmt.Errorf("failed to create HTTP client: %w", err)
 	}
 
 	// Create a request
 	req, err := http.NewRequest("GET", searchURL, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add headers to make the request look more like a browser
@@ -186,20 +388,23 @@ func tryWikipediaImageSearch(query string, imageSize int) ([]byte, string, error
 	logger.Info("Performing Wikipedia image search for query:", query)
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to connect to Wikipedia API: %w", err)
+		return "", fmt.Errorf("failed to connect to Wikipedia API: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response status code is not 200 OK
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("Wikipedia API returned error status %d: %s", resp.StatusCode, string(body))
+		return "", protocol.NewDataError(fmt.Errorf("Wikipedia API returned error status %d: %s", resp.StatusCode, string(body)), map[string]any{
+			"url":    transport.RedactURL(searchURL),
+			"status": resp.StatusCode,
+		})
 	}
 
 	// Read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read Wikipedia API response: %w", err)
+		return "", fmt.Errorf("failed to read Wikipedia API response: %w", err)
 	}
 
 	// Parse the JSON response
@@ -219,7 +424,7 @@ func tryWikipediaImageSearch(query string, imageSize int) ([]byte, string, error
 
 	err = json.Unmarshal(body, &apiResponse)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to parse Wikipedia API response: %w", err)
+		return "", fmt.Errorf("failed to parse Wikipedia API response: %w", err)
 	}
 
 	// Check if we got any pages with images
@@ -232,20 +437,10 @@ func tryWikipediaImageSearch(query string, imageSize int) ([]byte, string, error
 	}
 
 	if imageURL == "" {
-		return nil, "", fmt.Errorf("no image found for query: %s", query)
-	}
-
-	// Now fetch the actual image
-	logger.Info("Found image for", query, "at URL:", imageURL)
-
-	imageData, contentType, err := transport.GetImage(imageURL)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+		return "", fmt.Errorf("no image found for query: %s", query)
 	}
 
-	logger.Info("Successfully retrieved image for", query, "with size:", len(imageData), "bytes")
-
-	return imageData, contentType, nil
+	return imageURL, nil
 }
 
 // saveWikipediaImage saves an image from Wikipedia to disk with the correct file extension
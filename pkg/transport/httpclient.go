@@ -3,11 +3,11 @@ package transport
 import (
 	"compress/flate"
 	"compress/gzip"
-	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,8 +15,43 @@ import (
 
 	"github.com/andybalholm/brotli"
 	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
 )
 
+// sensitiveQueryParams are query parameters whose values are never included in error details
+var sensitiveQueryParams = []string{"key", "api_key", "apikey", "token", "access_token", "password", "secret", "sig", "signature"}
+
+// RedactURL returns the URL with credentials and sensitive query parameter values masked
+// so that it can safely be returned to a client or logged
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(unparseable url)"
+	}
+	if u.User != nil {
+		u.User = url.User("REDACTED")
+	}
+	query := u.Query()
+	for name := range query {
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.EqualFold(name, sensitive) {
+				query.Set(name, "REDACTED")
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// requestError attaches the (redacted) url and any status code to a failed request error
+func requestError(err error, requestURL string, status int) error {
+	data := map[string]any{"url": RedactURL(requestURL)}
+	if status != 0 {
+		data["status"] = status
+	}
+	return protocol.NewDataError(err, data)
+}
+
 var httpClient *http.Client
 
 // getZScalerBundle returns the Zscaler CA bundle if available
@@ -59,17 +94,30 @@ func GetCustomHTTPClient() (*http.Client, error) {
 		}
 	}
 
+	tlsConfig, err := NewTLSConfig(rootCAs)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := NewProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create custom transport with the certificate pool
 	customTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs: rootCAs,
-		},
-		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+		Proxy:           proxy,
+	}
+
+	var roundTripper http.RoundTripper = customTransport
+	if tlsConfig.InsecureSkipVerify {
+		roundTripper = &insecureTransport{base: customTransport}
 	}
 
 	// Create a custom client with the transport
 	client := &http.Client{
-		Transport: customTransport,
+		Transport: roundTripper,
 		Timeout:   30 * time.Second,
 		// CheckRedirect: nil means use default behavior (follow up to 10 redirects)
 		// You can customize this if needed
@@ -85,6 +133,12 @@ func GetCustomHTTPClient() (*http.Client, error) {
 	return client, nil
 }
 
+// SetCustomHTTPClient replaces the shared HTTP client, for example with one using a fake transport in tests.
+// Setting nil makes the next GetCustomHTTPClient build the usual client from the environment
+func SetCustomHTTPClient(client *http.Client) {
+	httpClient = client
+}
+
 // Attempts to get the bytes and filetype of an online image
 func GetHtml(htmlUrl string) ([]byte, error) {
 
@@ -110,13 +164,13 @@ func GetHtml(htmlUrl string) ([]byte, error) {
 	// Make the HTTP request
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch html: %w", err)
+		return nil, requestError(fmt.Errorf("failed to fetch html: %w", err), htmlUrl, 0)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response status code is not 200 OK
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request returned error status %d", resp.StatusCode)
+		return nil, requestError(fmt.Errorf("request returned error status %d", resp.StatusCode), htmlUrl, resp.StatusCode)
 	}
 
 	// handle compression (Content-Encoding)
@@ -199,19 +253,19 @@ func GetImage(imageUrl string) ([]byte, string, error) {
 	// Make the HTTP request for the image
 	imgResp, err := client.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+		return nil, "", requestError(fmt.Errorf("failed to fetch image: %w", err), imageUrl, 0)
 	}
 	defer imgResp.Body.Close()
 
 	// Check if the response status code is not 200 OK
 	if imgResp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("image request returned error status %d", imgResp.StatusCode)
+		return nil, "", requestError(fmt.Errorf("image request returned error status %d", imgResp.StatusCode), imageUrl, imgResp.StatusCode)
 	}
 
 	// Check if the response is actually an image
 	contentType := imgResp.Header.Get("Content-Type")
 	if !strings.HasPrefix(contentType, "image/") {
-		return nil, "", fmt.Errorf("response is not an image, content type: %s", contentType)
+		return nil, "", protocol.NewDataError(fmt.Errorf("response is not an image, content type: %s", contentType), map[string]any{"url": RedactURL(imageUrl), "contentType": contentType})
 	}
 
 	// Read the image data
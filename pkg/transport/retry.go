@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+)
+
+// RetryPolicy controls how often a request failing with a transient error is retried
+type RetryPolicy struct {
+	// Attempts is the total number of tries, including the first
+	Attempts int
+	// Backoff is the wait before the first retry, doubling before each one after
+	Backoff time.Duration
+}
+
+// DefaultRetryPolicy tries a request three times over about a second and a half
+var DefaultRetryPolicy = RetryPolicy{Attempts: 3, Backoff: 500 * time.Millisecond}
+
+// Do calls fn until it succeeds, fails with an error which isn't transient, or runs out of attempts.
+// The last error is returned
+func (p RetryPolicy) Do(fn func() error) error {
+	backoff := p.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !IsTransient(err) || attempt >= p.Attempts {
+			return err
+		}
+		logger.Info("Retrying after transient error, attempt", attempt, "of", p.Attempts, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// IsTransient reports whether a request error might not happen again if the request is retried:
+// connection failures, timeouts, dropped connections and 429 or 5xx statuses.
+// Statuses are read from the "status" of any protocol.DataError in the chain
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if data, ok := protocol.ErrorData(err).(map[string]any); ok {
+		if status, ok := data["status"].(int); ok {
+			return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+		}
+	}
+	return false
+}
@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/richard-senior/mcp/internal/logger"
 	"github.com/richard-senior/mcp/pkg/protocol"
@@ -15,17 +19,59 @@ import (
 // prettyPrint controls whether JSON responses include line breaks
 const prettyPrint = true
 
+// EnvMaxMessageSize sets the largest incoming message, in bytes, that the stdio transport will accept
+const EnvMaxMessageSize = "MCP_MAX_MESSAGE_SIZE"
+
+// DefaultMaxMessageSize is used when EnvMaxMessageSize isn't set
+const DefaultMaxMessageSize = 4 << 20
+
+// ErrMessageTooLarge is returned by ReadRequest when a message exceeds the maximum size.
+// The rest of the message has been skipped, so the next request can still be read
+var ErrMessageTooLarge = errors.New("message too large")
+
+// MaxMessageSize returns the maximum incoming message size from the environment
+func MaxMessageSize() int {
+	value := strings.TrimSpace(os.Getenv(EnvMaxMessageSize))
+	if value == "" {
+		return DefaultMaxMessageSize
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		logger.Warn("Ignoring invalid", EnvMaxMessageSize, value, "using the default of", DefaultMaxMessageSize)
+		return DefaultMaxMessageSize
+	}
+	return size
+}
+
 // StdioTransport implements communication over standard input/output
 type StdioTransport struct {
 	reader *bufio.Reader
 	writer *bufio.Writer
+	// maxMessageSize is the largest request that will be held in memory
+	maxMessageSize int
+	// writeMu serialises writes so that each message reaches the output as one frame
+	writeMu sync.Mutex
 }
 
 // NewStdioTransport creates a new transport that uses stdin/stdout
 func NewStdioTransport() *StdioTransport {
+	return NewStdioTransportWithIO(os.Stdin, os.Stdout)
+}
+
+// NewStdioTransportWithIO creates a new transport reading requests from in and writing responses to out
+// In MCP mode out should be the stdout returned by RedirectStdout so stray prints cannot reach it
+func NewStdioTransportWithIO(in io.Reader, out io.Writer) *StdioTransport {
 	return &StdioTransport{
-		reader: bufio.NewReader(os.Stdin),
-		writer: bufio.NewWriter(os.Stdout),
+		reader:         bufio.NewReader(in),
+		writer:         bufio.NewWriter(out),
+		maxMessageSize: MaxMessageSize(),
+	}
+}
+
+// SetMaxMessageSize changes the largest request that will be accepted
+func (t *StdioTransport) SetMaxMessageSize(size int) {
+	if size > 0 {
+		t.maxMessageSize = size
 	}
 }
 
@@ -38,6 +84,8 @@ func (t *StdioTransport) ReadRequest() (*protocol.JsonRpcRequest, error) {
 	var depth int
 	var inString bool
 	var escapeNext bool
+	// once a message is too large the rest of it is read and discarded rather than stored
+	var size int
 
 	for {
 		b, err := t.reader.ReadByte()
@@ -50,7 +98,10 @@ func (t *StdioTransport) ReadRequest() (*protocol.JsonRpcRequest, error) {
 			return nil, err
 		}
 
-		requestData = append(requestData, b)
+		size++
+		if size <= t.maxMessageSize {
+			requestData = append(requestData, b)
+		}
 
 		// Track string literals to avoid counting braces inside strings
 		if !escapeNext && b == '"' {
@@ -88,6 +139,11 @@ func (t *StdioTransport) ReadRequest() (*protocol.JsonRpcRequest, error) {
 		}
 	}
 
+	if size > t.maxMessageSize {
+		logger.Warn("Discarded a request of", size, "bytes, the maximum is", t.maxMessageSize)
+		return nil, fmt.Errorf("%w: request of %d bytes exceeds the maximum of %d bytes", ErrMessageTooLarge, size, t.maxMessageSize)
+	}
+
 	// Trim any whitespace
 	requestStr := strings.TrimSpace(string(requestData))
 	logger.Debug("Received raw request:", requestStr)
@@ -132,23 +188,44 @@ func (t *StdioTransport) WriteResponse(response *protocol.JsonRpcResponse) error
 		responseBytes = buf.Bytes()
 	}
 
-	// Add a newline to the response
-	responseBytes = append(responseBytes, '\n')
+	if err := t.writeFrame(responseBytes); err != nil {
+		return err
+	}
+	logger.Info("Response sent successfully")
+	return nil
+}
+
+// WriteNotification writes a JSON-RPC notification (a request without an ID) to stdout
+// Notifications may be written at any time, for example when the list of prompts changes
+func (t *StdioTransport) WriteNotification(notification *protocol.JsonRpcRequest) error {
+	notificationBytes, err := json.Marshal(notification)
+	if err != nil {
+		logger.Error("Failed to marshal notification:", err)
+		return err
+	}
+	return t.writeFrame(notificationBytes)
+}
+
+// writeFrame writes a single marshalled message followed by a newline and flushes it
+func (t *StdioTransport) writeFrame(data []byte) error {
+	// Add a newline to the message
+	data = append(data, '\n')
 
-	logger.Debug("Sending response:", string(responseBytes))
+	logger.Debug("Sending message:", string(data))
 
-	// Write the response to stdout
-	if _, err := t.writer.Write(responseBytes); err != nil {
-		logger.Error("Failed to write response:", err)
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	// Write the message to stdout
+	if _, err := t.writer.Write(data); err != nil {
+		logger.Error("Failed to write message:", err)
 		return err
 	}
 
-	// Flush to ensure the response is sent
+	// Flush to ensure the message is sent
 	if err := t.writer.Flush(); err != nil {
-		logger.Error("Failed to flush response:", err)
+		logger.Error("Failed to flush message:", err)
 		return err
 	}
-
-	logger.Info("Response sent successfully")
 	return nil
 }
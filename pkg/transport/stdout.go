@@ -0,0 +1,15 @@
+//go:build !mcpdebug
+
+package transport
+
+import (
+	"os"
+)
+
+// RedirectStdout points os.Stdout at stderr so that accidental prints from tools
+// cannot corrupt the JSON-RPC stream, and returns the original stdout for the transport
+func RedirectStdout() *os.File {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	return stdout
+}
@@ -0,0 +1,37 @@
+//go:build mcpdebug
+
+package transport
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/richard-senior/mcp/internal/logger"
+)
+
+// RedirectStdout points os.Stdout at a pipe and returns the original stdout for the transport
+// In debug builds anything written to the pipe is stray output which would have corrupted
+// the JSON-RPC stream, so it is reported as a warning before being passed on to stderr
+func RedirectStdout() *os.File {
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		logger.Warn("Failed to create stdout guard pipe, falling back to stderr:", err)
+		os.Stdout = os.Stderr
+		return stdout
+	}
+	os.Stdout = w
+	go watchStrayOutput(r, os.Stderr)
+	return stdout
+}
+
+// watchStrayOutput logs every line written to the redirected stdout and copies it to out
+func watchStrayOutput(r io.Reader, out io.Writer) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		logger.Warn("Stray output on stdout would have corrupted the JSON-RPC stream:", line)
+		io.WriteString(out, line+"\n")
+	}
+}
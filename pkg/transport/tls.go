@@ -0,0 +1,144 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/richard-senior/mcp/internal/logger"
+)
+
+// EnvTLSMinVersion sets the minimum TLS version of the shared HTTP client, one of 1.0, 1.1, 1.2 or 1.3
+const EnvTLSMinVersion = "MCP_TLS_MIN_VERSION"
+
+// EnvTLSCiphers restricts the shared HTTP client to a comma separated list of cipher suite names
+const EnvTLSCiphers = "MCP_TLS_CIPHERS"
+
+// EnvInsecureSkipVerify disables verification of server certificates when set to true.
+// This is only for debugging against misconfigured servers, as it allows any server to impersonate any other
+const EnvInsecureSkipVerify = "MCP_INSECURE_SKIP_TLS_VERIFY"
+
+// EnvInsecureSkipVerifyUnprefixed is also accepted in place of EnvInsecureSkipVerify
+const EnvInsecureSkipVerifyUnprefixed = "INSECURE_SKIP_TLS_VERIFY"
+
+// DefaultTLSMinVersion is used when EnvTLSMinVersion isn't set
+const DefaultTLSMinVersion = tls.VersionTLS12
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// InsecureSkipVerifyEnabled reports whether certificate verification has been disabled in the environment
+func InsecureSkipVerifyEnabled() bool {
+	return insecureSkipVerifyEnv() != ""
+}
+
+// insecureSkipVerifyEnv returns the name of the variable disabling certificate verification, if any
+func insecureSkipVerifyEnv() string {
+	for _, name := range []string{EnvInsecureSkipVerify, EnvInsecureSkipVerifyUnprefixed} {
+		v := strings.ToLower(strings.TrimSpace(os.Getenv(name)))
+		if v == "1" || v == "true" || v == "yes" {
+			return name
+		}
+	}
+	return ""
+}
+
+// warnInsecure writes a warning about disabled certificate verification to stderr.
+// It doesn't go through the logger as logging is turned off when running as an MCP server
+func warnInsecure(v ...any) {
+	fmt.Fprintln(os.Stderr, append([]any{"WARNING:"}, v...)...)
+}
+
+// ParseTLSVersion converts a version such as "1.2" to its crypto/tls constant.
+// An empty version gives DefaultTLSMinVersion
+func ParseTLSVersion(version string) (uint16, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "TLS")
+	if version == "" {
+		return DefaultTLSMinVersion, nil
+	}
+	v, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version %q, must be one of 1.0, 1.1, 1.2 or 1.3", version)
+	}
+	return v, nil
+}
+
+// ParseCipherSuites converts a comma separated list of cipher suite names, as named by
+// tls.CipherSuiteName, to their IDs. Suites known to be insecure are rejected.
+// An empty list gives nil, leaving the choice to crypto/tls
+func ParseCipherSuites(names string) ([]uint16, error) {
+	if strings.TrimSpace(names) == "" {
+		return nil, nil
+	}
+	secure := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.Name] = suite.ID
+	}
+	insecure := map[string]bool{}
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.Name] = true
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if insecure[name] {
+			return nil, fmt.Errorf("cipher suite %s is insecure and can't be allowed", name)
+		}
+		id, ok := secure[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// NewTLSConfig builds the TLS configuration of the shared HTTP client from the environment.
+// Lowering the minimum version below 1.2 is allowed, for testing against legacy endpoints,
+// but is logged as a warning every time as those versions have known weaknesses
+func NewTLSConfig(rootCAs *x509.CertPool) (*tls.Config, error) {
+	minVersion, err := ParseTLSVersion(os.Getenv(EnvTLSMinVersion))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvTLSMinVersion, err)
+	}
+	if minVersion < tls.VersionTLS12 {
+		logger.Warn("Allowing TLS versions below 1.2 as", EnvTLSMinVersion, "is set, connections may be downgraded to an insecure protocol")
+	}
+	ciphers, err := ParseCipherSuites(os.Getenv(EnvTLSCiphers))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvTLSCiphers, err)
+	}
+	insecureEnv := insecureSkipVerifyEnv()
+	insecure := insecureEnv != ""
+	if insecure {
+		warnInsecure("TLS certificate verification is DISABLED as", insecureEnv, "is set, connections can be intercepted")
+	}
+	return &tls.Config{
+		RootCAs:            rootCAs,
+		MinVersion:         minVersion,
+		CipherSuites:       ciphers,
+		InsecureSkipVerify: insecure,
+	}, nil
+}
+
+// insecureTransport warns on every request made without certificate verification,
+// so that it can't be left switched on unnoticed
+type insecureTransport struct {
+	base http.RoundTripper
+}
+
+func (t *insecureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	warnInsecure("Requesting", RedactURL(req.URL.String()), "WITHOUT verifying the server certificate,", insecureSkipVerifyEnv(), "is set")
+	return t.base.RoundTrip(req)
+}
@@ -105,7 +105,10 @@ func NewEllipticalArcFromGCode(currentPos Point, endPos Point, i, j float64, clo
 }
 
 // NewEllipticalArc creates a new elliptical arc with the given parameters
+// Out of range radii are corrected as described in the SVG spec (F.6.6), negative radii
+// are made positive and radii too small to reach the end point are scaled up
 func NewEllipticalArc(start, end Point, radiusX, radiusY, rotation float64, sweep, largeArc bool) *EllipticalArc {
+	radiusX, radiusY = correctArcRadii(start, end, radiusX, radiusY, rotation)
 	arc := &EllipticalArc{
 		Start:    start,
 		End:      end,
@@ -119,6 +122,63 @@ func NewEllipticalArc(start, end Point, radiusX, radiusY, rotation float64, swee
 	return arc
 }
 
+// correctArcRadii applies the SVG out of range radii correction for an arc from start to end
+// rotation is the x axis rotation of the ellipse in radians
+func correctArcRadii(start, end Point, radiusX, radiusY, rotation float64) (float64, float64) {
+	radiusX = math.Abs(radiusX)
+	radiusY = math.Abs(radiusY)
+	if radiusX == 0 || radiusY == 0 {
+		return radiusX, radiusY
+	}
+
+	// half the distance between the end points in the ellipse's own coordinate system
+	dx := (start.X - end.X) / 2
+	dy := (start.Y - end.Y) / 2
+	c := math.Cos(rotation)
+	s := math.Sin(rotation)
+	x1 := c*dx + s*dy
+	y1 := -s*dx + c*dy
+
+	// if the ellipse cannot reach both points scale it up until it exactly can
+	lambda := (x1*x1)/(radiusX*radiusX) + (y1*y1)/(radiusY*radiusY)
+	if lambda > 1 {
+		scale := math.Sqrt(lambda)
+		radiusX *= scale
+		radiusY *= scale
+	}
+	return radiusX, radiusY
+}
+
+// endpointArcCenter calculates the center of an SVG arc from its end point parameterisation
+// as described in the SVG spec (F.6.5). The radii must already have been corrected
+func endpointArcCenter(start, end Point, radiusX, radiusY, rotation float64, largeArc, sweep bool) Point {
+	c := math.Cos(rotation)
+	s := math.Sin(rotation)
+	dx := (start.X - end.X) / 2
+	dy := (start.Y - end.Y) / 2
+	x1 := c*dx + s*dy
+	y1 := -s*dx + c*dy
+
+	rx2 := radiusX * radiusX
+	ry2 := radiusY * radiusY
+	num := rx2*ry2 - rx2*y1*y1 - ry2*x1*x1
+	den := rx2*y1*y1 + ry2*x1*x1
+	coef := 0.0
+	if den > 0 && num > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cx := coef * radiusX * y1 / radiusY
+	cy := -coef * radiusY * x1 / radiusX
+
+	return Point{
+		X: c*cx - s*cy + (start.X+end.X)/2,
+		Y: s*cx + c*cy + (start.Y+end.Y)/2,
+	}
+}
+
 // NewEllipticalArcFromEllipse creates a new elliptical arc from an Ellipse struct
 func NewEllipticalArcFromEllipse(ellipse Ellipse, start, end Point) *EllipticalArc {
 	return NewEllipticalArc(
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+/// GCODE PROGRAM
+///////////////////////////////////////////////////////////////////////////////
+
+// Generates a complete GCode program cutting every path in turn.
+// The program sets the units and absolute positioning, runs the preamble, cuts each path
+// (lifting to SafeZ between them), runs the postamble and finishes with M30
+func GenerateGCodeProgram(paths *Paths, opts GCodeOptions) (string, error) {
+	if paths == nil || paths.NumPaths() == 0 {
+		return "", fmt.Errorf("must supply at least one path to generate a GCode program")
+	}
+	if opts.FeedRate <= 0 {
+		return "", fmt.Errorf("feed rate must be greater than zero")
+	}
+	if opts.SafeZ <= 0 {
+		return "", fmt.Errorf("safe Z height must be greater than zero")
+	}
+	if opts.CutZ >= opts.SafeZ {
+		return "", fmt.Errorf("cut Z height must be below the safe Z height")
+	}
+
+	var units string
+	switch strings.ToLower(opts.Units) {
+	case "", "mm":
+		units = "G21"
+	case "inch", "in":
+		units = "G20"
+	default:
+		return "", fmt.Errorf("unsupported units '%s', must be mm or inch", opts.Units)
+	}
+
+	format := opts.format()
+	word := func(letter string, value float64) string {
+		return (&GCodeParameter{Letter: letter, Value: value}).Format(format)
+	}
+
+	preamble := opts.Preamble
+	if preamble == nil {
+		spindleOn := "M03"
+		if opts.SpindleSpeed > 0 {
+			spindleOn += " " + word("S", opts.SpindleSpeed)
+		}
+		preamble = []string{spindleOn}
+	}
+	postamble := opts.Postamble
+	if postamble == nil {
+		postamble = []string{
+			"G00 " + word("Z", opts.SafeZ),
+			"M05",
+			"G00 " + word("X", 0) + " " + word("Y", 0),
+		}
+	}
+
+	lines := []string{units, "G90"}
+	lines = append(lines, preamble...)
+	for _, path := range paths.Paths {
+		gcode, err := path.ToGCodeWithOptions(opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert path '%s' to GCode: %v", path.ID, err)
+		}
+		if gcode != "" {
+			lines = append(lines, gcode)
+		}
+	}
+	lines = append(lines, postamble...)
+	lines = append(lines, "M30")
+	return strings.Join(lines, "\n") + "\n", nil
+}
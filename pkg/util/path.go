@@ -6,6 +6,7 @@ import (
 	"os"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/richard-senior/mcp/internal/logger"
@@ -69,6 +70,46 @@ func NewGCodeParameter(letter string, value float64) (*GCodeParameter, error) {
 	return ret, nil
 }
 
+// Controls how GCode parameter values are written.
+// Controllers differ in how many decimal places they accept and whether they like trailing zeros
+type GCodeFormat struct {
+	// The number of decimal places values are rounded to
+	Precision int
+	// Strip trailing zeros (and a trailing decimal point) so 2.000 is written as 2
+	TrimZeros bool
+}
+
+// The format used when no other is specified
+var DefaultGCodeFormat = GCodeFormat{Precision: 4, TrimZeros: true}
+
+// Formats a value according to this format
+func (f GCodeFormat) FormatValue(v float64) string {
+	precision := f.Precision
+	if precision < 0 {
+		precision = 0
+	}
+	ret := strconv.FormatFloat(v, 'f', precision, 64)
+	if f.TrimZeros && strings.Contains(ret, ".") {
+		ret = strings.TrimRight(ret, "0")
+		ret = strings.TrimSuffix(ret, ".")
+	}
+	// values which round to zero shouldn't be written as negative
+	if strings.Trim(ret, "-0.") == "" {
+		ret = strings.TrimPrefix(ret, "-")
+	}
+	return ret
+}
+
+// Renders this parameter such as X5.387 using the default format
+func (p *GCodeParameter) String() string {
+	return p.Format(DefaultGCodeFormat)
+}
+
+// Renders this parameter such as X5.387 using the given format
+func (p *GCodeParameter) Format(f GCodeFormat) string {
+	return p.Letter + f.FormatValue(p.Value)
+}
+
 // /////////////////////////////////////////////////////////////////////////////
 // / GCode
 // /////////////////////////////////////////////////////////////////////////////
@@ -126,6 +167,25 @@ func (c *GCode) OrderParameters() error {
 	return nil
 }
 
+// Renders this command such as 'G01 X5.387 Y2' using the default format
+func (c *GCode) String() string {
+	return c.Format(DefaultGCodeFormat)
+}
+
+// Renders this command using the given format with its parameters in the canonical order
+func (c *GCode) Format(f GCodeFormat) string {
+	ordered := &GCode{Letter: c.Letter, Params: append([]GCodeParameter{}, c.Params...)}
+	// a command such as M30 has no parameters to order
+	if len(ordered.Params) > 0 {
+		ordered.OrderParameters()
+	}
+	words := []string{c.Letter}
+	for _, param := range ordered.Params {
+		words = append(words, param.Format(f))
+	}
+	return strings.Join(words, " ")
+}
+
 // Converts this GRBL block into an SVG Path tag
 func (c *GCode) ToSvgPath() (*Path, error) {
 	return nil, nil
@@ -235,6 +295,9 @@ func NewPathCommand(cmd string) (*PathCommand, error) {
 
 // calculates the final coordinate (point) of this command
 func (pc *PathCommand) GetFinishPoint(prev *PathCommand) (*Point, error) {
+	if pc == nil {
+		return nil, fmt.Errorf("Cannot calculate the finish point of a nil PathCommand")
+	}
 	if pc.Letter == "" || pc.Params == nil {
 		return nil, fmt.Errorf("This PathCommand is not instantiated correctly yet")
 	}
@@ -255,21 +318,24 @@ func (pc *PathCommand) GetFinishPoint(prev *PathCommand) (*Point, error) {
 	case "m", "l":
 		return NewPoint(pp.X+pc.Params[0], pp.Y+pc.Params[1]), nil
 	case "H":
-		return NewPoint(pp.X, pc.Params[1]), nil
-	case "V":
+		// horizontal moves keep the previous Y
 		return NewPoint(pc.Params[0], pp.Y), nil
+	case "V":
+		// vertical moves keep the previous X
+		return NewPoint(pp.X, pc.Params[0]), nil
 	case "h":
-		return NewPoint(pp.X+pc.Params[0], pc.Params[1]), nil
+		return NewPoint(pp.X+pc.Params[0], pp.Y), nil
 	case "v":
-		return NewPoint(pc.Params[0], pp.Y+pc.Params[1]), nil
+		return NewPoint(pp.X, pp.Y+pc.Params[0]), nil
 	case "Q":
 		return NewPoint(pc.Params[2], pc.Params[3]), nil
 	case "q":
 		return NewPoint(pp.X+pc.Params[2], pp.Y+pc.Params[3]), nil
 	case "A":
-		return NewPoint(pc.Params[6], pc.Params[7]), nil
+		// rx ry x-axis-rotation large-arc-flag sweep-flag x y
+		return NewPoint(pc.Params[5], pc.Params[6]), nil
 	case "a":
-		return NewPoint(pp.X+pc.Params[6], pp.Y+pc.Params[7]), nil
+		return NewPoint(pp.X+pc.Params[5], pp.Y+pc.Params[6]), nil
 	case "Z", "z":
 		return nil, fmt.Errorf("Can't calculate the finish point of a Z command without the initial point of the path")
 	default:
@@ -437,6 +503,16 @@ func (pc *PathCommand) PointaliseByDistance(prev *PathCommand, maxDistance float
 			endPoint = Point{X: pp.X + pc.Params[5], Y: pp.Y + pc.Params[6]}
 		}
 
+		// The SVG spec treats an arc with a zero radius as a straight line
+		if rx == 0 || ry == 0 {
+			line := &PathCommand{Letter: "L", Params: []float64{endPoint.X, endPoint.Y}}
+			if err := line.PointaliseByDistance(prev, maxDistance); err != nil {
+				return err
+			}
+			pc.Points = line.Points
+			return nil
+		}
+
 		// Create an elliptical arc
 		arc := NewEllipticalArc(*startPoint, endPoint, rx, ry, xAxisRotation, sweepFlag, largeArcFlag)
 
@@ -472,6 +548,8 @@ type Path struct {
 	CommandsStr string
 	Commands    []*PathCommand
 	IsClosed    bool
+	// indices of the Points which start a new subpath, where Points jumps over a move
+	subpathStarts []int
 }
 
 func NewPathFromPoints(points []*Point, id string) (*Path, error) {
@@ -528,44 +606,187 @@ func NewPathFromSvgTag(tag string) (*Path, error) {
 	return ret, nil
 }
 
+// The number of parameters consumed by each supported SVG path command letter
+var pathCommandParamCounts = map[byte]int{
+	'M': 2, 'L': 2, 'H': 1, 'V': 1, 'Q': 4, 'A': 7, 'Z': 0,
+}
+
+// Valid SVG path commands which can't be processed yet. These are rejected when the path
+// is parsed rather than failing later when the path is pointalised
+var unsupportedPathCommands = map[byte]string{
+	'C': "cubic Bézier curve", 'S': "smooth cubic Bézier curve", 'T': "smooth quadratic Bézier curve",
+}
+
+// Parses CommandsStr into Commands.
+// Each command letter consumes the number of parameters it requires and is repeated for
+// any further parameter sets that follow it, with extra sets after a move being treated
+// as lines as the SVG spec requires. Numbers may be packed without separators
+// (e.g. 'M10-20.5.5') and arc flags may be written without separators (e.g. 'a5 5 0 0110,0')
 func (p *Path) ParsePathCommands() error {
 	if p.CommandsStr == "" {
 		return fmt.Errorf("Path must have a populated CommandsStr field before this method is called")
 	}
 
-	// Regular expression to match path commands: a letter followed by numbers
-	// This regex captures each command letter and its associated parameters
-	commandRegex := regexp.MustCompile(`([MLHVCSQTAZmlhvcsqtaz])[\s,]*([^MLHVCSQTAZmlhvcsqtaz]*)`)
-
-	// Find all matches
-	matches := commandRegex.FindAllStringSubmatch(p.CommandsStr, -1)
+	d := p.CommandsStr
+	commands := []*PathCommand{}
+	pos := skipPathSeparators(d, 0)
 
-	// If no matches found, return an error
-	if len(matches) == 0 {
-		return fmt.Errorf("no valid path commands found")
-	}
+	for pos < len(d) {
+		letter := d[pos]
+		upper := letter &^ 0x20
+		count, ok := pathCommandParamCounts[upper]
+		if name, unsupported := unsupportedPathCommands[upper]; unsupported {
+			return fmt.Errorf("command %c (%s) at position %d is not currently supported", letter, name, pos)
+		}
+		if !ok {
+			return fmt.Errorf("unexpected character '%c' at position %d", letter, pos)
+		}
+		pos = skipPathSeparators(d, pos+1)
 
-	// Parse each command
-	commands := make([]*PathCommand, 0, len(matches))
-	for _, match := range matches {
-		if len(match) >= 2 {
-			cmdStr := match[1]
-			if len(match) >= 3 && match[2] != "" {
-				cmdStr += " " + strings.TrimSpace(match[2])
+		// consume parameter sets until the next command letter
+		for set := 0; count > 0; set++ {
+			// once one set has been read the command ends at anything that isn't a number
+			if set > 0 && (pos >= len(d) || !isPathNumberStart(d[pos])) {
+				break
+			}
+			params := make([]float64, count)
+			for k := 0; k < count; k++ {
+				if pos >= len(d) {
+					return fmt.Errorf("command %c requires %d parameters but the path data ended", letter, count)
+				}
+				var err error
+				if upper == 'A' && (k == 3 || k == 4) {
+					params[k], pos, err = scanPathFlag(d, pos)
+				} else {
+					params[k], pos, err = scanPathNumber(d, pos)
+				}
+				if err != nil {
+					return fmt.Errorf("command %c parameter %d: %v", letter, k+1, err)
+				}
+				pos = skipPathSeparators(d, pos)
 			}
 
-			cmd, err := NewPathCommand(cmdStr)
+			cmdLetter := string(letter)
+			// further coordinate pairs after a move are implicit lines
+			if set > 0 && letter == 'M' {
+				cmdLetter = "L"
+			} else if set > 0 && letter == 'm' {
+				cmdLetter = "l"
+			}
+			cmd, err := newPathCommandFromParams(cmdLetter, params)
+			if err != nil {
+				return err
+			}
+			commands = append(commands, cmd)
+		}
+		if count == 0 {
+			cmd, err := newPathCommandFromParams(string(letter), nil)
 			if err != nil {
-				return fmt.Errorf("failed to parse command '%s': %v", cmdStr, err)
+				return err
 			}
 			commands = append(commands, cmd)
 		}
 	}
+
+	if len(commands) == 0 {
+		return fmt.Errorf("no valid path commands found")
+	}
 	// modify the instance
 	p.Commands = commands
 	return nil
 }
 
+// creates a PathCommand from a letter and already parsed parameters
+func newPathCommandFromParams(letter string, params []float64) (*PathCommand, error) {
+	if len(letter) != 1 {
+		return nil, fmt.Errorf("invalid command letter: %s", letter)
+	}
+	count, ok := pathCommandParamCounts[letter[0]&^0x20]
+	if !ok {
+		return nil, fmt.Errorf("command letter %s not currently supported", letter)
+	}
+	if len(params) != count {
+		return nil, fmt.Errorf("command %s requires exactly %d parameters", letter, count)
+	}
+	return &PathCommand{
+		Letter: letter,
+		Params: params,
+		Points: []*Point{},
+	}, nil
+}
+
+// returns the position of the next character in d which is not whitespace or a comma
+func skipPathSeparators(d string, pos int) int {
+	for pos < len(d) {
+		switch d[pos] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			pos++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// reports whether c can be the first character of a number in SVG path data
+func isPathNumberStart(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.' || c == '-' || c == '+'
+}
+
+// reads a single number starting at pos, returning the value and the position after it
+func scanPathNumber(d string, pos int) (float64, int, error) {
+	start := pos
+	if pos < len(d) && (d[pos] == '-' || d[pos] == '+') {
+		pos++
+	}
+	digits := 0
+	for pos < len(d) && d[pos] >= '0' && d[pos] <= '9' {
+		pos++
+		digits++
+	}
+	// a second decimal point starts the next number
+	if pos < len(d) && d[pos] == '.' {
+		pos++
+		for pos < len(d) && d[pos] >= '0' && d[pos] <= '9' {
+			pos++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, start, fmt.Errorf("expected a number at position %d", start)
+	}
+	// only treat e as an exponent if digits follow it
+	if pos < len(d) && (d[pos] == 'e' || d[pos] == 'E') {
+		exp := pos + 1
+		if exp < len(d) && (d[exp] == '-' || d[exp] == '+') {
+			exp++
+		}
+		if exp < len(d) && d[exp] >= '0' && d[exp] <= '9' {
+			for exp < len(d) && d[exp] >= '0' && d[exp] <= '9' {
+				exp++
+			}
+			pos = exp
+		}
+	}
+	v, err := strconv.ParseFloat(d[start:pos], 64)
+	if err != nil {
+		return 0, start, fmt.Errorf("invalid number '%s' at position %d", d[start:pos], start)
+	}
+	return v, pos, nil
+}
+
+// reads a single arc flag which is always one character, '0' or '1'
+func scanPathFlag(d string, pos int) (float64, int, error) {
+	switch d[pos] {
+	case '0':
+		return 0, pos + 1, nil
+	case '1':
+		return 1, pos + 1, nil
+	default:
+		return 0, pos, fmt.Errorf("expected an arc flag of 0 or 1 at position %d", pos)
+	}
+}
+
 func (p *Path) ParseSvgPathTag() error {
 	// Validate that it's a path tag using regex
 	if p.PathTag == "" {
@@ -612,9 +833,894 @@ func (p *Path) ParseSvgPathTag() error {
 	return nil
 }
 
-// Converts this path object to GRBL
+// The default maximum distance between points used when a Path is pointalised implicitly
+const DefaultMaxPointDistance = 0.5
+
+// Populates the Path's Points field by pointalising each of its commands in turn so that
+// consecutive points are no further apart than maxDistance.
+// Note that Points is a single polyline so any moves within the path are joined up.
+func (p *Path) Pointalise(maxDistance float64) error {
+	subpaths, err := p.pointaliseSubpaths(maxDistance)
+	if err != nil {
+		return err
+	}
+	points := []*Point{}
+	starts := []int{}
+	for _, sp := range subpaths {
+		before := len(points)
+		points = appendPoints(points, sp)
+		// a move which goes nowhere leaves nothing to jump over
+		if before > 0 && len(points)-before == len(sp) {
+			starts = append(starts, before)
+		}
+	}
+	p.Points = points
+	p.subpathStarts = starts
+	return nil
+}
+
+// Pointalises the commands into one polyline for each subpath, so that the moves
+// between subpaths are not part of any polyline
+func (p *Path) pointaliseSubpaths(maxDistance float64) ([][]*Point, error) {
+	if len(p.Commands) == 0 {
+		if p.CommandsStr == "" {
+			return nil, fmt.Errorf("Path has no commands to pointalise")
+		}
+		if err := p.ParsePathCommands(); err != nil {
+			return nil, err
+		}
+	}
+	if maxDistance <= 0 {
+		return nil, fmt.Errorf("maxDistance must be greater than zero")
+	}
+
+	// SVG paths start at the origin until the first move command
+	current := NewPoint(0, 0)
+	subpathStart := current
+	subpaths := [][]*Point{}
+	var points []*Point
+
+	for _, cmd := range p.Commands {
+		// describe the current point as an absolute move so relative commands can be resolved
+		prev := &PathCommand{Letter: "M", Params: []float64{current.X, current.Y}}
+
+		var finish *Point
+		switch cmd.Letter {
+		case "M", "m":
+			end, err := cmd.GetFinishPoint(prev)
+			if err != nil {
+				return nil, err
+			}
+			if points != nil {
+				subpaths = append(subpaths, points)
+			}
+			points = []*Point{end}
+			subpathStart = end
+			finish = end
+		case "Z", "z":
+			// close the subpath with a straight line back to where it started
+			closing := &PathCommand{Letter: "L", Params: []float64{subpathStart.X, subpathStart.Y}}
+			if err := closing.PointaliseByDistance(prev, maxDistance); err != nil {
+				return nil, err
+			}
+			points = appendPoints(points, closing.Points)
+			finish = subpathStart
+		default:
+			if err := cmd.PointaliseByDistance(prev, maxDistance); err != nil {
+				return nil, err
+			}
+			if len(cmd.Points) == 0 {
+				return nil, fmt.Errorf("command %s produced no points", cmd.Letter)
+			}
+			points = appendPoints(points, cmd.Points)
+			finish = cmd.Points[len(cmd.Points)-1]
+		}
+		current = finish
+	}
+	if points != nil {
+		subpaths = append(subpaths, points)
+	}
+	return subpaths, nil
+}
+
+// appends points to dst, skipping the first point if it duplicates the last point of dst
+func appendPoints(dst []*Point, points []*Point) []*Point {
+	if len(points) > 0 && len(dst) > 0 {
+		last := dst[len(dst)-1]
+		if last.X == points[0].X && last.Y == points[0].Y {
+			points = points[1:]
+		}
+	}
+	return append(dst, points...)
+}
+
+// makes sure the Points field is populated, pointalising the commands if necessary
+func (p *Path) ensurePoints() error {
+	if len(p.Points) > 0 {
+		return nil
+	}
+	return p.Pointalise(DefaultMaxPointDistance)
+}
+
+// makes sure the Commands field is populated, parsing CommandsStr if necessary
+func (p *Path) ensureCommands() error {
+	if len(p.Commands) > 0 {
+		return nil
+	}
+	if p.CommandsStr == "" {
+		return fmt.Errorf("Path has no commands")
+	}
+	return p.ParsePathCommands()
+}
+
+// Replaces the commands of this path, rewriting CommandsStr to match.
+// The path tag is cleared so that ToPathTag renders the new commands.
+func (p *Path) setCommands(commands []*PathCommand) {
+	strs := make([]string, len(commands))
+	for i, cmd := range commands {
+		strs[i] = strings.TrimSpace(cmd.Letter + " " + formatPathParams(cmd.Params))
+	}
+	p.Commands = commands
+	p.CommandsStr = strings.Join(strs, " ")
+	p.PathTag = ""
+}
+
+// Moves this path by dx and dy.
+// Only absolute coordinates are moved, relative commands are offsets from a point which has already moved.
+// Any Points are moved too
+func (p *Path) Translate(dx, dy float64) error {
+	if err := p.ensureCommands(); err != nil {
+		return err
+	}
+	commands := make([]*PathCommand, 0, len(p.Commands))
+	for i, cmd := range p.Commands {
+		params := append([]float64(nil), cmd.Params...)
+		// a relative move at the very start of a path is relative to the origin
+		absolute := StringIsUpper(cmd.Letter) || (i == 0 && cmd.Letter == "m")
+		if absolute {
+			switch strings.ToUpper(cmd.Letter) {
+			case "M", "L":
+				params[0] += dx
+				params[1] += dy
+			case "H":
+				params[0] += dx
+			case "V":
+				params[0] += dy
+			case "Q":
+				params[0] += dx
+				params[1] += dy
+				params[2] += dx
+				params[3] += dy
+			case "A":
+				// the radii, rotation and flags describe the shape of the arc and don't move
+				params[5] += dx
+				params[6] += dy
+			}
+		}
+		commands = append(commands, &PathCommand{Letter: cmd.Letter, Params: params, Points: []*Point{}})
+	}
+	p.setCommands(commands)
+	for i, pt := range p.Points {
+		p.Points[i] = NewPoint(pt.X+dx, pt.Y+dy)
+	}
+	return nil
+}
+
+// Scales this path by sx and sy relative to the origin.
+// Absolute coordinates and relative offsets are both scaled, and arcs are reshaped to the scaled ellipse.
+// Any Points are scaled too
+func (p *Path) Scale(sx, sy float64) error {
+	if err := p.ensureCommands(); err != nil {
+		return err
+	}
+	commands := make([]*PathCommand, 0, len(p.Commands))
+	for _, cmd := range p.Commands {
+		params := append([]float64(nil), cmd.Params...)
+		switch strings.ToUpper(cmd.Letter) {
+		case "M", "L":
+			params[0] *= sx
+			params[1] *= sy
+		case "H":
+			params[0] *= sx
+		case "V":
+			params[0] *= sy
+		case "Q":
+			params[0] *= sx
+			params[1] *= sy
+			params[2] *= sx
+			params[3] *= sy
+		case "A":
+			params[0], params[1], params[2] = scaleArcEllipse(params[0], params[1], params[2], sx, sy)
+			// mirroring the path reverses the direction the arc sweeps in
+			if sx*sy < 0 {
+				params[4] = 1 - params[4]
+			}
+			params[5] *= sx
+			params[6] *= sy
+		}
+		commands = append(commands, &PathCommand{Letter: cmd.Letter, Params: params, Points: []*Point{}})
+	}
+	p.setCommands(commands)
+	for i, pt := range p.Points {
+		p.Points[i] = NewPoint(pt.X*sx, pt.Y*sy)
+	}
+	return nil
+}
+
+// Calculates the radii and rotation (in degrees) of an arc's ellipse after scaling by sx and sy.
+// Scaling a rotated ellipse unevenly changes both its axes and its rotation, which are found from
+// the axes of the scaled ellipse's matrix
+func scaleArcEllipse(rx, ry, rotation, sx, sy float64) (float64, float64, float64) {
+	ax, ay := math.Abs(sx), math.Abs(sy)
+	if ax == ay {
+		return rx * ax, ry * ax, rotation
+	}
+	if math.Mod(rotation, 180) == 0 {
+		return rx * ax, ry * ay, rotation
+	}
+	if math.Mod(rotation, 90) == 0 {
+		return rx * ay, ry * ax, rotation
+	}
+
+	phi := rotation * math.Pi / 180
+	// the images of the ellipse's two semi-axes
+	ux, uy := sx*rx*math.Cos(phi), sy*rx*math.Sin(phi)
+	vx, vy := -sx*ry*math.Sin(phi), sy*ry*math.Cos(phi)
+	e := ux*ux + vx*vx
+	f := ux*uy + vx*vy
+	g := uy*uy + vy*vy
+	mid := (e + g) / 2
+	spread := math.Hypot((e-g)/2, f)
+	major := math.Sqrt(mid + spread)
+	minor := math.Sqrt(math.Max(0, mid-spread))
+	angle := math.Atan2(2*f, e-g) / 2 * 180 / math.Pi
+	return major, minor, angle
+}
+
+// one run of drawing commands from a start point, as used when reversing a path
+type subpath struct {
+	start    *Point
+	commands []*PathCommand
+	closed   bool
+}
+
+// Reverses the direction of this path so that it starts where it used to finish.
+// The subpaths are reversed individually and in reverse order, so each keeps its own move and close,
+// and curves and arcs are kept. The commands are made absolute in the process.
+// Any Points are reversed too
+func (p *Path) Reverse() error {
+	if err := p.ensureCommands(); err != nil {
+		return err
+	}
+	points := p.Points
+	if err := p.ToAbsolute(); err != nil {
+		return err
+	}
+
+	// split the commands into subpaths at each move, and after each close
+	subpaths := []*subpath{}
+	current := NewPoint(0, 0)
+	var sp *subpath
+	for _, cmd := range p.Commands {
+		switch cmd.Letter {
+		case "M":
+			current = NewPoint(cmd.Params[0], cmd.Params[1])
+			sp = &subpath{start: current}
+			subpaths = append(subpaths, sp)
+			continue
+		case "Z":
+			if sp != nil {
+				sp.closed = true
+				current = sp.start
+				sp = nil
+			}
+			continue
+		}
+		if sp == nil {
+			// drawing without a move starts from wherever the last subpath finished
+			sp = &subpath{start: current}
+			subpaths = append(subpaths, sp)
+		}
+		sp.commands = append(sp.commands, cmd)
+		end, err := cmd.GetFinishPoint(&PathCommand{Letter: "M", Params: []float64{current.X, current.Y}})
+		if err != nil {
+			return err
+		}
+		current = end
+	}
+
+	commands := []*PathCommand{}
+	for i := len(subpaths) - 1; i >= 0; i-- {
+		reversed, err := subpaths[i].reverse()
+		if err != nil {
+			return err
+		}
+		commands = append(commands, reversed...)
+	}
+	p.setCommands(commands)
+
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	p.Points = points
+	// the jump before point i now comes before the point that was at i-1
+	starts := make([]int, len(p.subpathStarts))
+	for i, start := range p.subpathStarts {
+		starts[len(starts)-1-i] = len(points) - start
+	}
+	p.subpathStarts = starts
+	return nil
+}
+
+// returns the absolute commands which draw this subpath in the opposite direction.
+// A closed subpath still starts and closes at its original start point
+func (s *subpath) reverse() ([]*PathCommand, error) {
+	// the point each command starts from
+	starts := make([]*Point, len(s.commands))
+	current := s.start
+	for i, cmd := range s.commands {
+		starts[i] = current
+		end, err := cmd.GetFinishPoint(&PathCommand{Letter: "M", Params: []float64{current.X, current.Y}})
+		if err != nil {
+			return nil, err
+		}
+		current = end
+	}
+	finish := current
+
+	move := func(pt *Point) *PathCommand {
+		return &PathCommand{Letter: "M", Params: []float64{pt.X, pt.Y}, Points: []*Point{}}
+	}
+	commands := []*PathCommand{}
+	if s.closed {
+		commands = append(commands, move(s.start))
+		// the closing line is the first thing drawn when going backwards
+		if finish.X != s.start.X || finish.Y != s.start.Y {
+			commands = append(commands, &PathCommand{Letter: "L", Params: []float64{finish.X, finish.Y}, Points: []*Point{}})
+		}
+	} else {
+		commands = append(commands, move(finish))
+	}
+
+	for i := len(s.commands) - 1; i >= 0; i-- {
+		cmd, start := s.commands[i], starts[i]
+		var params []float64
+		switch cmd.Letter {
+		case "L":
+			params = []float64{start.X, start.Y}
+		case "H":
+			params = []float64{start.X}
+		case "V":
+			params = []float64{start.Y}
+		case "Q":
+			// the control point is the same in either direction
+			params = []float64{cmd.Params[0], cmd.Params[1], start.X, start.Y}
+		case "A":
+			// the same ellipse swept the other way
+			params = []float64{cmd.Params[0], cmd.Params[1], cmd.Params[2], cmd.Params[3], 1 - cmd.Params[4], start.X, start.Y}
+		default:
+			return nil, fmt.Errorf("command letter %s not currently supported", cmd.Letter)
+		}
+		commands = append(commands, &PathCommand{Letter: cmd.Letter, Params: params, Points: []*Point{}})
+	}
+
+	if s.closed {
+		commands = append(commands, &PathCommand{Letter: "Z", Points: []*Point{}})
+	}
+	return commands, nil
+}
+
+// Rewrites any relative (lower case) commands as their absolute equivalents by tracking the
+// current point, so that later processing only has to deal with absolute coordinates.
+// Commands and CommandsStr are both updated, the geometry (and so Points) is unchanged.
+// The path tag is cleared so that ToPathTag renders the new commands.
+func (p *Path) ToAbsolute() error {
+	if len(p.Commands) == 0 {
+		if p.CommandsStr == "" {
+			return fmt.Errorf("Path has no commands to make absolute")
+		}
+		if err := p.ParsePathCommands(); err != nil {
+			return err
+		}
+	}
+
+	// SVG paths start at the origin until the first move command
+	current := NewPoint(0, 0)
+	subpathStart := current
+	commands := make([]*PathCommand, 0, len(p.Commands))
+	commandStrs := make([]string, 0, len(p.Commands))
+
+	for _, cmd := range p.Commands {
+		letter := strings.ToUpper(cmd.Letter)
+		params := append([]float64(nil), cmd.Params...)
+		relative := StringIsLower(cmd.Letter)
+
+		switch letter {
+		case "M", "L":
+			if relative {
+				params[0] += current.X
+				params[1] += current.Y
+			}
+			current = NewPoint(params[0], params[1])
+			if letter == "M" {
+				subpathStart = current
+			}
+		case "H":
+			if relative {
+				params[0] += current.X
+			}
+			current = NewPoint(params[0], current.Y)
+		case "V":
+			if relative {
+				params[0] += current.Y
+			}
+			current = NewPoint(current.X, params[0])
+		case "Q":
+			if relative {
+				params[0] += current.X
+				params[1] += current.Y
+				params[2] += current.X
+				params[3] += current.Y
+			}
+			current = NewPoint(params[2], params[3])
+		case "A":
+			// only the end point is relative, the radii, rotation and flags are unchanged
+			if relative {
+				params[5] += current.X
+				params[6] += current.Y
+			}
+			current = NewPoint(params[5], params[6])
+		case "Z":
+			// closing returns to the start of the subpath
+			current = subpathStart
+		default:
+			return fmt.Errorf("command letter %s not currently supported", cmd.Letter)
+		}
+
+		abs, err := newPathCommandFromParams(letter, params)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, abs)
+		commandStrs = append(commandStrs, strings.TrimSpace(letter+" "+formatPathParams(abs.Params)))
+	}
+
+	p.Commands = commands
+	p.CommandsStr = strings.Join(commandStrs, " ")
+	p.PathTag = ""
+	return nil
+}
+
+// formats command parameters as they are written in path data, separated by spaces
+func formatPathParams(params []float64) string {
+	strs := make([]string, len(params))
+	for i, v := range params {
+		// write negative zero, e.g. from scaling by a negative factor, as 0
+		if v == 0 {
+			v = 0
+		}
+		strs[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.Join(strs, " ")
+}
+
+// Calculates the extents of this path from its Points, pointalising the commands first if necessary
+func (p *Path) BoundingBox() (minX, minY, maxX, maxY float64, err error) {
+	if p == nil {
+		return 0, 0, 0, 0, fmt.Errorf("Cannot calculate the bounding box of a nil Path")
+	}
+	if err := p.ensurePoints(); err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("Cannot calculate the bounding box of path '%s': %v", p.ID, err)
+	}
+	if len(p.Points) == 0 {
+		return 0, 0, 0, 0, fmt.Errorf("Path '%s' has no points", p.ID)
+	}
+
+	minX, minY = p.Points[0].X, p.Points[0].Y
+	maxX, maxY = minX, minY
+	for _, pt := range p.Points[1:] {
+		minX = math.Min(minX, pt.X)
+		minY = math.Min(minY, pt.Y)
+		maxX = math.Max(maxX, pt.X)
+		maxY = math.Max(maxY, pt.Y)
+	}
+	return minX, minY, maxX, maxY, nil
+}
+
+// Calculates the total drawn length of this path by summing the lengths of its subpaths.
+// The moves between subpaths aren't drawn so aren't included.
+func (p *Path) Length() float64 {
+	if p == nil {
+		return 0
+	}
+	segments, err := p.segments(false)
+	if err != nil {
+		logger.Warn("Cannot calculate the length of path", p.ID, err)
+		return 0
+	}
+	var length float64
+	for _, l := range segments {
+		length += distance(l.Start, l.End)
+	}
+	return length
+}
+
+// Returns exactly n points spaced equally by arc length along the drawn subpaths,
+// starting at the first point and finishing at the last.
+// No points are placed on the moves between subpaths
+func (p *Path) Resample(n int) []*Point {
+	if p == nil || n < 1 {
+		return nil
+	}
+	subpaths, err := p.drawnSubpaths()
+	if err != nil {
+		logger.Warn("Cannot resample path", p.ID, err)
+		return nil
+	}
+	if len(subpaths) == 0 {
+		return nil
+	}
+	segments := polylineSegments(subpaths, false)
+	var total float64
+	for _, l := range segments {
+		total += distance(l.Start, l.End)
+	}
+	if n == 1 || total == 0 {
+		first := subpaths[0][0]
+		ret := make([]*Point, n)
+		for i := range ret {
+			ret[i] = NewPoint(first.X, first.Y)
+		}
+		return ret
+	}
+	first, last := segments[0].Start, segments[len(segments)-1].End
+
+	step := total / float64(n-1)
+	ret := make([]*Point, 0, n)
+	ret = append(ret, NewPoint(first.X, first.Y))
+
+	// walk along the segments, emitting a point every time another step has been covered
+	seg := 0
+	segStart := 0.0
+	segLength := distance(segments[0].Start, segments[0].End)
+	for i := 1; i < n-1; i++ {
+		target := step * float64(i)
+		for seg < len(segments)-1 && segStart+segLength < target {
+			segStart += segLength
+			seg++
+			segLength = distance(segments[seg].Start, segments[seg].End)
+		}
+		a, b := segments[seg].Start, segments[seg].End
+		t := 0.0
+		if segLength > 0 {
+			t = math.Min(1, (target-segStart)/segLength)
+		}
+		ret = append(ret, NewPoint(a.X+t*(b.X-a.X), a.Y+t*(b.Y-a.Y)))
+	}
+
+	return append(ret, NewPoint(last.X, last.Y))
+}
+
+// The straight line distance between two points
+func distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// Returns true if the path finishes where it started, either explicitly with Z or by its points
+func (p *Path) isClosedLoop() bool {
+	if p.IsClosed {
+		return true
+	}
+	if len(p.Points) < 3 {
+		return false
+	}
+	first, last := p.Points[0], p.Points[len(p.Points)-1]
+	return first.X == last.X && first.Y == last.Y
+}
+
+// Returns the Points drawn by each subpath of this path, pointalising the commands first if necessary.
+// Points are only split where a move was found when they were pointalised
+func (p *Path) drawnSubpaths() ([][]*Point, error) {
+	if err := p.ensurePoints(); err != nil {
+		return nil, err
+	}
+	if len(p.Points) == 0 {
+		return nil, fmt.Errorf("Path '%s' has no points", p.ID)
+	}
+	subpaths := [][]*Point{}
+	start := 0
+	for _, next := range p.subpathStarts {
+		if next <= start || next >= len(p.Points) {
+			continue
+		}
+		subpaths = append(subpaths, p.Points[start:next])
+		start = next
+	}
+	subpaths = append(subpaths, p.Points[start:])
+	return subpaths, nil
+}
+
+// Returns the line segments drawn by this path, without the moves between its subpaths.
+// A closed path of a single subpath is closed back to its start if its points don't already,
+// and if closeSubpaths is true every subpath is, as it is when filled
+func (p *Path) segments(closeSubpaths bool) ([]Line, error) {
+	subpaths, err := p.drawnSubpaths()
+	if err != nil {
+		return nil, err
+	}
+	return polylineSegments(subpaths, closeSubpaths || (p.IsClosed && len(subpaths) == 1)), nil
+}
+
+// Returns the line segments joining consecutive points within each polyline
+func polylineSegments(polylines [][]*Point, closePolylines bool) []Line {
+	lines := []Line{}
+	for _, points := range polylines {
+		for i := 1; i < len(points); i++ {
+			lines = append(lines, Line{Start: *points[i-1], End: *points[i]})
+		}
+		first, last := points[0], points[len(points)-1]
+		if closePolylines && len(points) > 2 && (first.X != last.X || first.Y != last.Y) {
+			lines = append(lines, Line{Start: *last, End: *first})
+		}
+	}
+	return lines
+}
+
+// Reports whether pt lies inside this path using ray casting over its points.
+// Only closed paths enclose an area so an open path never contains a point.
+func (p *Path) ContainsPoint(pt *Point) bool {
+	if p == nil || pt == nil {
+		return false
+	}
+	if err := p.ensurePoints(); err != nil {
+		logger.Warn("Cannot test containment for path", p.ID, err)
+		return false
+	}
+	if !p.isClosedLoop() {
+		return false
+	}
+
+	segments, err := p.segments(true)
+	if err != nil {
+		logger.Warn("Cannot test containment for path", p.ID, err)
+		return false
+	}
+
+	// count how many edges a horizontal ray from pt to +infinity crosses
+	inside := false
+	for _, l := range segments {
+		if (l.Start.Y > pt.Y) != (l.End.Y > pt.Y) {
+			crossX := l.Start.X + (pt.Y-l.Start.Y)*(l.End.X-l.Start.X)/(l.End.Y-l.Start.Y)
+			if pt.X < crossX {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Reports whether any segment of path a crosses or touches any segment of path b.
+// The moves between subpaths aren't drawn so can't intersect
+func PathsIntersect(a, b *Path) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	aSegments, err := a.segments(false)
+	if err != nil {
+		logger.Warn("Cannot test intersection for path", a.ID, err)
+		return false
+	}
+	bSegments, err := b.segments(false)
+	if err != nil {
+		logger.Warn("Cannot test intersection for path", b.ID, err)
+		return false
+	}
+	for _, s1 := range aSegments {
+		for _, s2 := range bSegments {
+			if segmentsIntersect(s1, s2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reports whether two line segments cross or touch
+func segmentsIntersect(l1, l2 Line) bool {
+	d1 := crossProduct(l2.Start, l2.End, l1.Start)
+	d2 := crossProduct(l2.Start, l2.End, l1.End)
+	d3 := crossProduct(l1.Start, l1.End, l2.Start)
+	d4 := crossProduct(l1.Start, l1.End, l2.End)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	// collinear end points which lie on the other segment
+	return (d1 == 0 && onSegment(l2, l1.Start)) ||
+		(d2 == 0 && onSegment(l2, l1.End)) ||
+		(d3 == 0 && onSegment(l1, l2.Start)) ||
+		(d4 == 0 && onSegment(l1, l2.End))
+}
+
+// The z component of the cross product of (b - a) and (c - a)
+func crossProduct(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// Reports whether pt, known to be collinear with l, lies within the extents of l
+func onSegment(l Line, pt Point) bool {
+	return pt.X >= math.Min(l.Start.X, l.End.X) && pt.X <= math.Max(l.Start.X, l.End.X) &&
+		pt.Y >= math.Min(l.Start.Y, l.End.Y) && pt.Y <= math.Max(l.Start.Y, l.End.Y)
+}
+
+// Options controlling how paths are converted to GCode
+type GCodeOptions struct {
+	// Emit circular arcs as G02/G03 moves with I/J center offsets rather than many short
+	// G01 segments. Elliptical arcs can't be expressed this way and are always linearised
+	UseArcs bool
+	// The maximum distance between points when curves are linearised.
+	// Defaults to DefaultMaxPointDistance
+	MaxPointDistance float64
+	// How values are written, defaults to DefaultGCodeFormat
+	Format *GCodeFormat
+
+	// The following are used by GenerateGCodeProgram.
+	// When SafeZ is set each path move lifts to SafeZ, travels, then plunges to CutZ at FeedRate
+
+	// "mm" (the default) or "inch"
+	Units string
+	// The cutting feed rate in units per minute
+	FeedRate float64
+	// The height the tool is raised to before travelling between paths
+	SafeZ float64
+	// The height the tool cuts at, usually zero or a negative depth
+	CutZ float64
+	// The spindle speed sent with M03, omitted if zero
+	SpindleSpeed float64
+	// Lines written after the units and positioning setup, defaults to starting the spindle
+	Preamble []string
+	// Lines written before the final M30, defaults to lifting the tool, stopping the spindle
+	// and returning to the origin
+	Postamble []string
+}
+
+// returns the configured format or the default
+func (o GCodeOptions) format() GCodeFormat {
+	if o.Format != nil {
+		return *o.Format
+	}
+	return DefaultGCodeFormat
+}
+
+// Converts this path object to GCode moves using the default options
 func (p *Path) ToGCode() (string, error) {
-	return "", nil
+	return p.ToGCodeWithOptions(GCodeOptions{})
+}
+
+// Converts this path object to GCode moves, one per line.
+// Moves (M) become rapid G00 moves and everything else is cut with G01 (or G02/G03)
+func (p *Path) ToGCodeWithOptions(opts GCodeOptions) (string, error) {
+	if len(p.Commands) == 0 {
+		if p.CommandsStr == "" {
+			return "", fmt.Errorf("Path has no commands to convert to GCode")
+		}
+		if err := p.ParsePathCommands(); err != nil {
+			return "", err
+		}
+	}
+	maxDistance := opts.MaxPointDistance
+	if maxDistance <= 0 {
+		maxDistance = DefaultMaxPointDistance
+	}
+
+	format := opts.format()
+	lines := []string{}
+	move := func(code string, pt *Point) {
+		g := &GCode{Letter: code, Params: []GCodeParameter{{Letter: "X", Value: pt.X}, {Letter: "Y", Value: pt.Y}}}
+		lines = append(lines, g.Format(format))
+	}
+	// travel to pt, lifting the tool first and plunging afterwards if a safe height is set
+	travel := func(pt *Point) {
+		if opts.SafeZ <= 0 {
+			move("G00", pt)
+			return
+		}
+		lift := &GCode{Letter: "G00", Params: []GCodeParameter{{Letter: "Z", Value: opts.SafeZ}}}
+		lines = append(lines, lift.Format(format))
+		move("G00", pt)
+		plunge := &GCode{Letter: "G01", Params: []GCodeParameter{{Letter: "Z", Value: opts.CutZ}}}
+		if opts.FeedRate > 0 {
+			plunge.Params = append(plunge.Params, GCodeParameter{Letter: "F", Value: opts.FeedRate})
+		}
+		lines = append(lines, plunge.Format(format))
+	}
+
+	// SVG paths start at the origin until the first move command
+	current := NewPoint(0, 0)
+	subpathStart := current
+
+	for _, cmd := range p.Commands {
+		prev := &PathCommand{Letter: "M", Params: []float64{current.X, current.Y}}
+
+		switch cmd.Letter {
+		case "M", "m":
+			end, err := cmd.GetFinishPoint(prev)
+			if err != nil {
+				return "", err
+			}
+			travel(end)
+			subpathStart = end
+			current = end
+		case "Z", "z":
+			if current.X != subpathStart.X || current.Y != subpathStart.Y {
+				move("G01", subpathStart)
+			}
+			current = subpathStart
+		case "L", "l", "H", "h", "V", "v":
+			// straight lines need only a single move to their end
+			end, err := cmd.GetFinishPoint(prev)
+			if err != nil {
+				return "", err
+			}
+			move("G01", end)
+			current = end
+		case "A", "a":
+			if opts.UseArcs {
+				if g, end, ok := circularArcGCode(cmd, current); ok {
+					lines = append(lines, g.Format(format))
+					current = end
+					continue
+				}
+			}
+			fallthrough
+		default:
+			if err := cmd.PointaliseByDistance(prev, maxDistance); err != nil {
+				return "", err
+			}
+			if len(cmd.Points) == 0 {
+				return "", fmt.Errorf("command %s produced no points", cmd.Letter)
+			}
+			for _, pt := range cmd.Points[1:] {
+				move("G01", pt)
+			}
+			current = cmd.Points[len(cmd.Points)-1]
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Converts an arc command starting at current into a single G02/G03 move if the arc is circular.
+// Returns false if the arc is elliptical (or degenerate) and so must be linearised instead
+func circularArcGCode(cmd *PathCommand, current *Point) (*GCode, *Point, bool) {
+	end := Point{X: cmd.Params[5], Y: cmd.Params[6]}
+	if cmd.Letter == "a" {
+		end = Point{X: current.X + cmd.Params[5], Y: current.Y + cmd.Params[6]}
+	}
+	if end.X == current.X && end.Y == current.Y {
+		return nil, nil, false
+	}
+	rotation := cmd.Params[2] * math.Pi / 180.0
+	rx, ry := correctArcRadii(*current, end, cmd.Params[0], cmd.Params[1], rotation)
+	if rx == 0 || ry == 0 || math.Abs(rx-ry) > 1e-9*math.Max(rx, ry) {
+		return nil, nil, false
+	}
+
+	largeArc := cmd.Params[3] != 0
+	sweep := cmd.Params[4] != 0
+	center := endpointArcCenter(*current, end, rx, ry, rotation, largeArc, sweep)
+
+	// a positive angle (sweep) arc is counterclockwise in the GCode coordinate system
+	code := "G02"
+	if sweep {
+		code = "G03"
+	}
+	g := &GCode{Letter: code, Params: []GCodeParameter{
+		{Letter: "X", Value: end.X},
+		{Letter: "Y", Value: end.Y},
+		{Letter: "I", Value: center.X - current.X},
+		{Letter: "J", Value: center.Y - current.Y},
+	}}
+	return g, NewPoint(end.X, end.Y), true
 }
 
 func (p *Path) ToPathTag() (string, error) {
@@ -672,6 +1778,155 @@ func (p *Paths) AddPath(path *Path) {
 	p.Paths = append(p.Paths, path)
 }
 
+// Moves every path in this object by dx and dy
+func (p *Paths) Translate(dx, dy float64) error {
+	for _, path := range p.Paths {
+		if err := path.Translate(dx, dy); err != nil {
+			return fmt.Errorf("failed to translate path '%s': %v", path.ID, err)
+		}
+	}
+	return nil
+}
+
+// Scales every path in this object by sx and sy relative to the origin
+func (p *Paths) Scale(sx, sy float64) error {
+	for _, path := range p.Paths {
+		if err := path.Scale(sx, sy); err != nil {
+			return fmt.Errorf("failed to scale path '%s': %v", path.ID, err)
+		}
+	}
+	return nil
+}
+
+// Reverses the direction of every path in this object
+func (p *Paths) Reverse() error {
+	for _, path := range p.Paths {
+		if err := path.Reverse(); err != nil {
+			return fmt.Errorf("failed to reverse path '%s': %v", path.ID, err)
+		}
+	}
+	return nil
+}
+
+// Calculates the extents enclosing every path in this object
+func (p *Paths) BoundingBox() (minX, minY, maxX, maxY float64, err error) {
+	if p == nil || p.NumPaths() == 0 {
+		return 0, 0, 0, 0, fmt.Errorf("Cannot calculate the bounding box of an empty Paths object")
+	}
+	for i, path := range p.Paths {
+		x0, y0, x1, y1, err := path.BoundingBox()
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if i == 0 {
+			minX, minY, maxX, maxY = x0, y0, x1, y1
+			continue
+		}
+		minX = math.Min(minX, x0)
+		minY = math.Min(minY, y0)
+		maxX = math.Max(maxX, x1)
+		maxY = math.Max(maxY, y1)
+	}
+	return minX, minY, maxX, maxY, nil
+}
+
+// Returns the first and last points of a path, pointalising it if necessary
+func (p *Path) endPoints() (*Point, *Point, error) {
+	if err := p.ensurePoints(); err != nil {
+		return nil, nil, fmt.Errorf("path '%s': %v", p.ID, err)
+	}
+	if len(p.Points) == 0 {
+		return nil, nil, fmt.Errorf("path '%s' has no points", p.ID)
+	}
+	return p.Points[0], p.Points[len(p.Points)-1], nil
+}
+
+// Calculates the total distance travelled between paths (with the tool up) when they are
+// cut in their current order, starting from the origin
+func (p *Paths) TravelDistance() (float64, error) {
+	current := Point{X: 0, Y: 0}
+	var total float64
+	for _, path := range p.Paths {
+		start, end, err := path.endPoints()
+		if err != nil {
+			return 0, err
+		}
+		total += distance(current, *start)
+		current = *end
+	}
+	return total, nil
+}
+
+// Reorders the paths to reduce the travel between them using a nearest neighbour heuristic,
+// always cutting next whichever remaining path starts (or, if allowReverse is set, ends)
+// closest to where the tool is. Paths are reversed with Reverse, so keep their subpaths and curves.
+// The original order is kept if the heuristic doesn't improve on it.
+// Returns the travel distance before and after optimisation.
+func (p *Paths) OptimizeTravelOrder(allowReverse bool) (before float64, after float64, err error) {
+	before, err = p.TravelDistance()
+	if err != nil {
+		return 0, 0, err
+	}
+	if p.NumPaths() < 2 {
+		return before, before, nil
+	}
+
+	remaining := append([]*Path{}, p.Paths...)
+	ordered := make([]*Path, 0, len(remaining))
+	reverse := map[*Path]bool{}
+	current := Point{X: 0, Y: 0}
+
+	for len(remaining) > 0 {
+		best, bestReversed := 0, false
+		bestDistance := math.Inf(1)
+		for i, path := range remaining {
+			start, end, _ := path.endPoints()
+			if d := distance(current, *start); d < bestDistance {
+				best, bestReversed, bestDistance = i, false, d
+			}
+			// closed paths finish where they start so there is nothing to gain by reversing them
+			if allowReverse && !path.isClosedLoop() {
+				if d := distance(current, *end); d < bestDistance {
+					best, bestReversed, bestDistance = i, true, d
+				}
+			}
+		}
+		path := remaining[best]
+		remaining = append(remaining[:best], remaining[best+1:]...)
+		ordered = append(ordered, path)
+		start, end, _ := path.endPoints()
+		if bestReversed {
+			reverse[path] = true
+			current = *start
+		} else {
+			current = *end
+		}
+	}
+
+	// work out the new travel without modifying anything in case it is no better
+	after = 0
+	current = Point{X: 0, Y: 0}
+	for _, path := range ordered {
+		start, end, _ := path.endPoints()
+		if reverse[path] {
+			start, end = end, start
+		}
+		after += distance(current, *start)
+		current = *end
+	}
+	if after >= before {
+		return before, before, nil
+	}
+
+	for path := range reverse {
+		if err := path.Reverse(); err != nil {
+			return 0, 0, err
+		}
+	}
+	p.Paths = ordered
+	return before, after, nil
+}
+
 // Renders all paths in this object to a linebreak delimited string
 // of SVG <path> tags
 func (p *Paths) ToSVG() (string, error) {
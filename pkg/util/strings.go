@@ -15,7 +15,7 @@ import (
  */
 func IsFuzzyMatch(str1, str2 string) bool {
 	ld := FuzzyMatch(str1, str2)
-	logger.Info("Levenshtein distance for " + str1 + " and " + str2 + " is " + string(ld))
+	logger.Info("Levenshtein distance for " + str1 + " and " + str2 + " is " + strconv.Itoa(ld))
 	threshold := 2
 	return ld <= threshold
 }
@@ -0,0 +1,169 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+/// TEXT
+///////////////////////////////////////////////////////////////////////////////
+
+// Converts text into SVG path geometry using the glyph outlines of a font.
+// TrueType (.ttf) fonts and OpenType fonts with either TrueType or CFF (.otf) outlines are
+// supported, font collections (.ttc) are not. TrueType outlines are quadratic and are kept
+// exactly, CFF outlines are cubic and each curve is split into quadratics which stay within
+// GlyphCurveTolerance of it, as PathCommand has no cubic commands.
+// One Path is returned per visible glyph, positioned along a baseline at y=0 using the
+// font's advance widths and kerning. size is the font size (em height) in output units.
+// Characters the font has no glyph for are skipped with a warning.
+func TextToPaths(text string, fontPath string, size float64) (*Paths, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("font size must be greater than zero")
+	}
+	content, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font file %s: %v", fontPath, err)
+	}
+	f, err := sfnt.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font %s: %v", fontPath, err)
+	}
+
+	var buf sfnt.Buffer
+	ppem := fixed.Int26_6(size * 64)
+	ret, _ := NewPaths(nil)
+	var x fixed.Int26_6
+	var prev sfnt.GlyphIndex
+
+	for i, r := range text {
+		idx, err := f.GlyphIndex(&buf, r)
+		if err != nil || idx == 0 {
+			logger.Warn("Font has no glyph for character, skipping", string(r))
+			prev = 0
+			continue
+		}
+		if prev != 0 {
+			if kern, err := f.Kern(&buf, prev, idx, ppem, font.HintingNone); err == nil {
+				x += kern
+			}
+		}
+
+		segments, err := f.LoadGlyph(&buf, idx, ppem, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load glyph for '%c': %v", r, err)
+		}
+		// glyphs such as spaces have no outline but still advance
+		if len(segments) > 0 {
+			path, err := glyphToPath(segments, fixedToFloat(x), fmt.Sprintf("glyph_%d", i))
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert glyph for '%c': %v", r, err)
+			}
+			ret.AddPath(path)
+		}
+
+		advance, err := f.GlyphAdvance(&buf, idx, ppem, font.HintingNone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get advance for '%c': %v", r, err)
+		}
+		x += advance
+		prev = idx
+	}
+	return ret, nil
+}
+
+// The furthest the quadratics replacing a cubic glyph curve may stray from it, in output units
+const GlyphCurveTolerance = 0.01
+
+// Builds a closed Path from a glyph outline, offset horizontally by offsetX.
+// sfnt outlines already have y increasing downwards as SVG does.
+func glyphToPath(segments sfnt.Segments, offsetX float64, id string) (*Path, error) {
+	var d strings.Builder
+	toPoint := func(p fixed.Point26_6) Point {
+		return Point{X: fixedToFloat(p.X) + offsetX, Y: fixedToFloat(p.Y)}
+	}
+	pt := func(p Point) string {
+		return fmt.Sprintf("%.4f,%.4f", p.X, p.Y)
+	}
+
+	var current Point
+	for i, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			if i > 0 {
+				d.WriteString("Z ")
+			}
+			current = toPoint(seg.Args[0])
+			d.WriteString("M " + pt(current) + " ")
+		case sfnt.SegmentOpLineTo:
+			current = toPoint(seg.Args[0])
+			d.WriteString("L " + pt(current) + " ")
+		case sfnt.SegmentOpQuadTo:
+			current = toPoint(seg.Args[1])
+			d.WriteString("Q " + pt(toPoint(seg.Args[0])) + " " + pt(current) + " ")
+		case sfnt.SegmentOpCubeTo:
+			// cubic curves (from CFF fonts) aren't supported by PathCommand
+			quads := CubicToQuadratics(current, toPoint(seg.Args[0]), toPoint(seg.Args[1]), toPoint(seg.Args[2]), GlyphCurveTolerance)
+			for _, q := range quads {
+				d.WriteString("Q " + pt(q[0]) + " " + pt(q[1]) + " ")
+			}
+			current = toPoint(seg.Args[2])
+		}
+	}
+	d.WriteString("Z")
+
+	path := &Path{
+		ID:          id,
+		CommandsStr: d.String(),
+		Commands:    []*PathCommand{},
+		IsClosed:    true,
+	}
+	if err := path.ParsePathCommands(); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+// Approximates the cubic Bézier curve p0,c1,c2,p3 with quadratic curves, returning the control
+// and end point of each in order. Each quadratic uses the control point which best matches both
+// cubic controls, and the cubic is halved until that is within tolerance of it everywhere
+func CubicToQuadratics(p0, c1, c2, p3 Point, tolerance float64) [][2]Point {
+	return appendCubicQuadratics(nil, p0, c1, c2, p3, tolerance, 0)
+}
+
+// Halving a cubic divides its approximation error by 8, so this depth is never reached
+// by a usable tolerance, it only guards against a zero or negative one
+const maxCubicSplitDepth = 16
+
+func appendCubicQuadratics(dst [][2]Point, p0, c1, c2, p3 Point, tolerance float64, depth int) [][2]Point {
+	// the furthest the midpoint quadratic strays from the cubic is sqrt(3)/36 * |p3 - 3c2 + 3c1 - p0|
+	dx := p3.X - 3*c2.X + 3*c1.X - p0.X
+	dy := p3.Y - 3*c2.Y + 3*c1.Y - p0.Y
+	if depth >= maxCubicSplitDepth || math.Sqrt(3)/36*math.Hypot(dx, dy) <= tolerance {
+		control := Point{
+			X: (3*(c1.X+c2.X) - p0.X - p3.X) / 4,
+			Y: (3*(c1.Y+c2.Y) - p0.Y - p3.Y) / 4,
+		}
+		return append(dst, [2]Point{control, p3})
+	}
+
+	// split at t=0.5 using de Casteljau's algorithm
+	mid := func(a, b Point) Point { return Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2} }
+	p01, p12, p23 := mid(p0, c1), mid(c1, c2), mid(c2, p3)
+	p012, p123 := mid(p01, p12), mid(p12, p23)
+	m := mid(p012, p123)
+	dst = appendCubicQuadratics(dst, p0, p01, p012, m, tolerance, depth+1)
+	return appendCubicQuadratics(dst, m, p123, p23, p3, tolerance, depth+1)
+}
+
+// Converts a 26.6 fixed point value to a float
+func fixedToFloat(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
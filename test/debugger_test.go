@@ -0,0 +1,43 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/debugger"
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/tools"
+)
+
+// TestDebuggerErrorData tests only errors from Delve carry their error type in the error data
+func TestDebuggerErrorData(t *testing.T) {
+	handlers := map[string]func(any) (any, error){
+		"launch":     tools.HandleGoDebugLaunch,
+		"breakpoint": tools.HandleGoDebugSetBreakpoint,
+		"eval":       tools.HandleGoDebugEvalVariable,
+	}
+	for name, handler := range handlers {
+		_, err := handler(map[string]interface{}{})
+		if err == nil {
+			t.Errorf("%s: expected an error for missing parameters", name)
+			continue
+		}
+		if data := protocol.ErrorData(err); data != nil {
+			t.Errorf("%s: expected no error data for invalid arguments, got %v", name, data)
+		}
+	}
+}
+
+func TestDelveErrorUnwraps(t *testing.T) {
+	cause := io.ErrUnexpectedEOF
+	err := fmt.Errorf("close: %w", &debugger.DelveError{Op: "detach", Err: cause})
+	var delveErr *debugger.DelveError
+	if !errors.As(err, &delveErr) || !errors.Is(err, cause) {
+		t.Errorf("Expected the Delve error and its cause in the chain of %v", err)
+	}
+	if err.Error() != "close: detach failed: unexpected EOF" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
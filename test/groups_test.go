@@ -0,0 +1,45 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/server"
+)
+
+func TestParseToolGroups(t *testing.T) {
+	all := map[string]bool{}
+	for _, group := range server.ToolGroups {
+		all[group] = true
+	}
+	withThoughts := map[string]bool{server.ToolGroupThoughts: true}
+	for group := range all {
+		withThoughts[group] = true
+	}
+	cases := map[string]map[string]bool{
+		"":                all,
+		"all":             all,
+		"search, ALL":     all,
+		"all,thoughts":    withThoughts,
+		"thoughts":        {"thoughts": true},
+		"meme, thoughts":  {"meme": true, "thoughts": true},
+		"search,markdown": {"search": true, "markdown": true},
+		" debug ,,":       {"debug": true},
+		"search,unknown":  {"search": true},
+		"unknown":         {},
+	}
+	for list, expected := range cases {
+		groups := server.ParseToolGroups(list)
+		if !reflect.DeepEqual(groups, expected) {
+			t.Errorf("ParseToolGroups(%q) = %v, expected %v", list, groups, expected)
+		}
+	}
+}
+
+func TestEnabledToolGroupsFromEnvironment(t *testing.T) {
+	t.Setenv(server.EnvToolGroups, "svg")
+	groups := server.EnabledToolGroups()
+	if !groups[server.ToolGroupSvg] || groups[server.ToolGroupSearch] {
+		t.Errorf("Expected only the svg group to be enabled, got %v", groups)
+	}
+}
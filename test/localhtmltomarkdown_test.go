@@ -0,0 +1,91 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/tools"
+)
+
+const samplePage = "<html><head><title>Elvis</title></head><body><h1>Elvis Presley</h1><p>The King</p></body></html>"
+
+func TestLocalHTMLToMarkdown(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "elvis.html"), []byte(samplePage), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := tools.LocalHTMLToMarkdown(root, "elvis.html", filepath.Join("out", "elvis.md"))
+	if err != nil {
+		t.Fatalf("Failed to convert local html: %v", err)
+	}
+	markdown := result["markdown"].(string)
+	if !strings.Contains(markdown, "# Elvis Presley") || !strings.Contains(markdown, "The King") {
+		t.Errorf("Unexpected markdown: %s", markdown)
+	}
+	if result["title"] != "Elvis" {
+		t.Errorf("Expected title Elvis, got %v", result["title"])
+	}
+	written, err := os.ReadFile(result["filepath"].(string))
+	if err != nil {
+		t.Fatalf("Expected markdown to be written to the output path: %v", err)
+	}
+	if string(written) != markdown {
+		t.Errorf("Expected written file to contain the markdown, got %s", written)
+	}
+}
+
+func TestLocalHTMLToMarkdownOutsideRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(parent, "secret.html")
+	if err := os.WriteFile(outside, []byte(samplePage), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "page.html"), []byte(samplePage), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link.html")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"../secret.html", outside, "link.html"} {
+		if _, err := tools.LocalHTMLToMarkdown(root, path, ""); err == nil || !strings.Contains(err.Error(), "outside the permitted root") {
+			t.Errorf("Expected %s to be rejected as outside the root, got %v", path, err)
+		}
+	}
+	if _, err := tools.LocalHTMLToMarkdown(root, "page.html", "../escaped.md"); err == nil {
+		t.Error("Expected an output path outside the root to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escaped.md")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written outside the root")
+	}
+}
+
+func TestLocalHTMLToMarkdownRejectsNonHTML(t *testing.T) {
+	root := t.TempDir()
+	// a png signature with an html extension
+	if err := os.WriteFile(filepath.Join(root, "image.html"), []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("just some notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "page.txt"), []byte(samplePage), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"image.html", "notes.txt"} {
+		if _, err := tools.LocalHTMLToMarkdown(root, path, ""); err == nil || !strings.Contains(err.Error(), "not an HTML file") {
+			t.Errorf("Expected %s to be rejected as not html, got %v", path, err)
+		}
+	}
+	if _, err := tools.LocalHTMLToMarkdown(root, "page.txt", ""); err != nil {
+		t.Errorf("Expected html content to be accepted whatever the extension: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/tools"
+)
+
+// blankTemplate creates a plain grey image to use as a meme template
+func blankTemplate(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{Y: 128}), image.Point{}, draw.Src)
+	return img
+}
+
+func TestSaveMemePNG(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "memes", "cat.jpg")
+	location, err := tools.SaveMemePNG(blankTemplate(400, 300), "When the build is finally green", outputPath)
+	if err != nil {
+		t.Fatalf("Failed to save meme: %v", err)
+	}
+	if !strings.HasSuffix(location, filepath.Join("memes", "cat.png")) {
+		t.Errorf("Expected the extension to be replaced with .png, got %s", location)
+	}
+
+	file, err := os.Open(location)
+	if err != nil {
+		t.Fatalf("Failed to open saved meme: %v", err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("Saved meme is not a valid PNG: %v", err)
+	}
+
+	// the caption should only change pixels inside the caption box
+	box := tools.MemeCaptionBox(img.Bounds())
+	captioned := false
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if r>>8 == 128 && g>>8 == 128 && b>>8 == 128 {
+				continue
+			}
+			// allow for the outline spilling a few pixels past the box
+			if !image.Pt(x, y).In(box.Inset(-5)) {
+				t.Fatalf("Caption drawn outside the caption box at %d,%d", x, y)
+			}
+			captioned = true
+		}
+	}
+	if !captioned {
+		t.Error("Expected caption to be drawn on the image")
+	}
+}
+
+func TestSaveMemePNGTextTooLong(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "meme")
+	text := strings.Repeat("far too many words for this tiny template ", 10)
+	if _, err := tools.SaveMemePNG(blankTemplate(120, 120), text, outputPath); err == nil {
+		t.Fatal("Expected an error when the caption does not fit")
+	}
+	if _, err := os.Stat(outputPath + ".png"); !os.IsNotExist(err) {
+		t.Error("Expected no file to be written when the caption does not fit")
+	}
+}
+
+func TestRenderMemeCaptionSmallImage(t *testing.T) {
+	// too short for even the smallest font to be an eighth of the height
+	img, err := tools.RenderMemeCaption(blankTemplate(200, 60), "Tiny")
+	if err != nil {
+		t.Fatalf("Expected a caption on a small image: %v", err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 200, 60) {
+		t.Errorf("Expected the image size to be kept, got %v", img.Bounds())
+	}
+}
@@ -0,0 +1,37 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/server"
+)
+
+func TestSuggestMethods(t *testing.T) {
+	methods := []string{"initialize", "tools/list", "tools/call", "prompts/list", "prompts/get", "resources/list"}
+	cases := map[string][]string{
+		"tools/call ":  {"tools/call"},
+		"Tools/Call":   {"tools/call"},
+		"tools/cal":    {"tools/call"},
+		"prompt/lists": {"prompts/list"},
+		"initialise":   {"initialize"},
+		"shutdown":     nil,
+	}
+	for method, expected := range cases {
+		suggestions := server.SuggestMethods(method, methods)
+		if !reflect.DeepEqual(suggestions, expected) {
+			t.Errorf("SuggestMethods(%q) = %v, expected %v", method, suggestions, expected)
+		}
+	}
+}
+
+func TestSuggestMethodsLimitsSuggestions(t *testing.T) {
+	methods := []string{"tools/a", "tools/b", "tools/c", "tools/d"}
+	suggestions := server.SuggestMethods("tools/x", methods)
+	if len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %v", suggestions)
+	}
+	if suggestions[0] != "tools/a" {
+		t.Errorf("Expected equally close suggestions in name order, got %v", suggestions)
+	}
+}
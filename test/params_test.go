@@ -0,0 +1,62 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/server"
+)
+
+type callParams struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+func TestDecodeParams(t *testing.T) {
+	inputs := []any{
+		json.RawMessage(`{"name":"echo","arguments":{"text":"hi"}}`),
+		map[string]any{"name": "echo", "arguments": map[string]any{"text": "hi"}},
+	}
+	for _, input := range inputs {
+		var params callParams
+		if err := server.DecodeParams(input, &params); err != nil {
+			t.Fatalf("DecodeParams(%v) failed: %v", input, err)
+		}
+		if params.Name != "echo" || params.Arguments["text"] != "hi" {
+			t.Errorf("DecodeParams(%v) gave %+v", input, params)
+		}
+	}
+
+	var params callParams
+	if err := server.DecodeParams(nil, &params); err != nil || params.Name != "" {
+		t.Errorf("Expected nil params to decode to nothing, got %+v, %v", params, err)
+	}
+}
+
+func TestDecodeParamsErrors(t *testing.T) {
+	var params callParams
+	err := server.DecodeParams(json.RawMessage(`{"name":42}`), &params)
+	if err == nil || err.Error() != "name must be a string, not number" {
+		t.Errorf("Expected the bad field to be named, got %v", err)
+	}
+	if data, ok := protocol.ErrorData(err).(map[string]any); !ok || data["field"] != "name" {
+		t.Errorf("Expected the field in the error data, got %v", protocol.ErrorData(err))
+	}
+
+	err = server.DecodeParams(json.RawMessage(`{"arguments":[1,2]}`), &params)
+	if err == nil || !strings.Contains(err.Error(), "arguments must be an object") {
+		t.Errorf("Expected arguments to be reported as needing an object, got %v", err)
+	}
+
+	err = server.DecodeParams(json.RawMessage(`["echo"]`), &params)
+	if err == nil || !strings.Contains(err.Error(), "params must be an object") {
+		t.Errorf("Expected params to be reported as needing an object, got %v", err)
+	}
+
+	err = server.DecodeParams(json.RawMessage(`{"name":`), &params)
+	if err == nil {
+		t.Error("Expected truncated params to fail")
+	}
+}
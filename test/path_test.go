@@ -0,0 +1,962 @@
+package test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/util"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const pathTolerance = 1e-6
+
+// assertPoints checks that the points of a path match the expected coordinates
+func assertPoints(t *testing.T, got []*util.Point, want [][2]float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d points, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if math.Abs(got[i].X-w[0]) > pathTolerance || math.Abs(got[i].Y-w[1]) > pathTolerance {
+			t.Errorf("Point %d: expected (%g,%g), got (%g,%g)", i, w[0], w[1], got[i].X, got[i].Y)
+		}
+	}
+}
+
+func newTestPath(t *testing.T, d string) *util.Path {
+	t.Helper()
+	path, err := util.NewPathFromSvgTag(`<path id="p" d="` + d + `" />`)
+	if err != nil {
+		t.Fatalf("Failed to create path from '%s': %v", d, err)
+	}
+	return path
+}
+
+func TestPathTranslate(t *testing.T) {
+	path := newTestPath(t, "M 0,0 L 2,0 L 2,1")
+	if err := path.Pointalise(10); err != nil {
+		t.Fatalf("Pointalise failed: %v", err)
+	}
+	if err := path.Translate(3, -1); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	assertPoints(t, path.Points, [][2]float64{{3, -1}, {5, -1}, {5, 0}})
+	if path.Commands[0].Letter != "M" || path.Commands[0].Params[0] != 3 || path.Commands[0].Params[1] != -1 {
+		t.Errorf("Expected commands to be rewritten from the translated points, got %s", path.CommandsStr)
+	}
+}
+
+func TestPathScale(t *testing.T) {
+	path := newTestPath(t, "M 1,1 L 3,1 L 3,2 Z")
+	if err := path.Pointalise(10); err != nil {
+		t.Fatalf("Pointalise failed: %v", err)
+	}
+	if err := path.Scale(2, -0.5); err != nil {
+		t.Fatalf("Scale failed: %v", err)
+	}
+	assertPoints(t, path.Points, [][2]float64{{2, -0.5}, {6, -0.5}, {6, -1}, {2, -0.5}})
+	if !path.IsClosed {
+		t.Error("Expected scaled path to remain closed")
+	}
+	last := path.Commands[len(path.Commands)-1]
+	if last.Letter != "Z" {
+		t.Errorf("Expected closed path to end with Z, got %s", path.CommandsStr)
+	}
+}
+
+func TestPathReverse(t *testing.T) {
+	path := newTestPath(t, "M 0,0 l 1,0 l 0,2")
+	if err := path.Pointalise(10); err != nil {
+		t.Fatalf("Pointalise failed: %v", err)
+	}
+	if err := path.Reverse(); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	assertPoints(t, path.Points, [][2]float64{{1, 2}, {1, 0}, {0, 0}})
+	if path.IsClosed {
+		t.Error("Expected open path to remain open")
+	}
+}
+
+func TestPathTransformsKeepSubpaths(t *testing.T) {
+	path := newTestPath(t, "M0 0 L1 0 L1 1 Z M5 5 L6 5 L6 6 Z")
+	if err := path.Translate(10, 0); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	expected := "M 10 0 L 11 0 L 11 1 Z M 15 5 L 16 5 L 16 6 Z"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, path.CommandsStr)
+	}
+
+	if err := path.Scale(2, 1); err != nil {
+		t.Fatalf("Scale failed: %v", err)
+	}
+	expected = "M 20 0 L 22 0 L 22 1 Z M 30 5 L 32 5 L 32 6 Z"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, path.CommandsStr)
+	}
+
+	// each subpath is reversed in place, starting and closing where it did before
+	if err := path.Reverse(); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	expected = "M 30 5 L 32 6 L 32 5 L 30 5 Z M 20 0 L 22 1 L 22 0 L 20 0 Z"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, path.CommandsStr)
+	}
+}
+
+func TestPathTransformsKeepRelativeCommands(t *testing.T) {
+	path := newTestPath(t, "m 1,1 l 2,0 v 3 h -2 z")
+	if err := path.Translate(5, 5); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	// only the initial move, which is relative to the origin, is moved
+	expected := "m 6 6 l 2 0 v 3 h -2 z"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, path.CommandsStr)
+	}
+	if err := path.Scale(2, -1); err != nil {
+		t.Fatalf("Scale failed: %v", err)
+	}
+	expected = "m 12 -6 l 4 0 v -3 h -4 z"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, path.CommandsStr)
+	}
+}
+
+func TestPathTransformsKeepArcs(t *testing.T) {
+	d := "M 0,0 A 5 5 0 0 1 10,0 Q 15,5 20,0"
+	path := newTestPath(t, d)
+	if err := path.Translate(1, 2); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	expected := "M 1 2 A 5 5 0 0 1 11 2 Q 16 7 21 2"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, path.CommandsStr)
+	}
+
+	// mirroring vertically flips the direction of the sweep
+	if err := path.Scale(2, -2); err != nil {
+		t.Fatalf("Scale failed: %v", err)
+	}
+	expected = "M 2 -4 A 10 10 0 0 0 22 -4 Q 32 -14 42 -4"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, path.CommandsStr)
+	}
+
+	original := newTestPath(t, d)
+	reversed := newTestPath(t, d)
+	if err := reversed.Reverse(); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	expected = "M 20 0 Q 15 5 10 0 A 5 5 0 0 0 0 0"
+	if reversed.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, reversed.CommandsStr)
+	}
+	// the reversed arc bulges the same way, so covers the same ground
+	_, minY, _, maxY, err := original.BoundingBox()
+	if err != nil {
+		t.Fatalf("BoundingBox failed: %v", err)
+	}
+	_, rMinY, _, rMaxY, err := reversed.BoundingBox()
+	if err != nil {
+		t.Fatalf("BoundingBox failed: %v", err)
+	}
+	if math.Abs(minY-rMinY) > 0.01 || math.Abs(maxY-rMaxY) > 0.01 {
+		t.Errorf("Expected the same vertical extent, got %g..%g and %g..%g", minY, maxY, rMinY, rMaxY)
+	}
+	if math.Abs(original.Length()-reversed.Length()) > 0.01 {
+		t.Errorf("Expected the same length, got %g and %g", original.Length(), reversed.Length())
+	}
+}
+
+func TestPathScaleRotatedArc(t *testing.T) {
+	// a circle stays a circle whatever its rotation, scaling it unevenly gives an axis aligned ellipse
+	path := newTestPath(t, "M 0,0 A 5 5 30 0 1 10,0")
+	if err := path.Scale(2, 1); err != nil {
+		t.Fatalf("Scale failed: %v", err)
+	}
+	arc := path.Commands[1]
+	if math.Abs(arc.Params[0]-10) > pathTolerance || math.Abs(arc.Params[1]-5) > pathTolerance {
+		t.Errorf("Expected radii 10 and 5, got %g and %g", arc.Params[0], arc.Params[1])
+	}
+	if math.Abs(math.Mod(arc.Params[2], 180)) > pathTolerance {
+		t.Errorf("Expected the ellipse to be axis aligned, got rotation %g", arc.Params[2])
+	}
+}
+
+func TestPathsTranslate(t *testing.T) {
+	a := newTestPath(t, "M 0,0 L 1,1")
+	if err := a.Pointalise(10); err != nil {
+		t.Fatalf("Pointalise failed: %v", err)
+	}
+	b, err := util.NewPathFromPoints([]*util.Point{util.NewPoint(5, 5), util.NewPoint(6, 5)}, "b")
+	if err != nil {
+		t.Fatalf("Failed to create path: %v", err)
+	}
+	paths, _ := util.NewPaths([]*util.Path{a, b})
+	if err := paths.Translate(1, 2); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	assertPoints(t, a.Points, [][2]float64{{1, 2}, {2, 3}})
+	assertPoints(t, b.Points, [][2]float64{{6, 7}, {7, 7}})
+}
+
+func TestPathsBoundingBox(t *testing.T) {
+	line := newTestPath(t, "M -2,1 L 3,4")
+	// the curve peaks at y=5 halfway along even though both end points are at y=0
+	curve := newTestPath(t, "M 0,0 Q 5,10 10,0")
+	paths, _ := util.NewPaths([]*util.Path{line, curve})
+
+	_, _, _, maxY, err := curve.BoundingBox()
+	if err != nil {
+		t.Fatalf("BoundingBox failed: %v", err)
+	}
+	if math.Abs(maxY-5) > 0.01 {
+		t.Errorf("Expected curve maxY to be 5, got %g", maxY)
+	}
+
+	minX, minY, maxX, maxY, err := paths.BoundingBox()
+	if err != nil {
+		t.Fatalf("BoundingBox failed: %v", err)
+	}
+	want := [4]float64{-2, 0, 10, 5}
+	got := [4]float64{minX, minY, maxX, maxY}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 0.01 {
+			t.Errorf("Expected bounding box %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestPathBoundingBoxEmpty(t *testing.T) {
+	if _, _, _, _, err := (&util.Path{}).BoundingBox(); err == nil {
+		t.Error("Expected an error for an uninitialised path")
+	}
+	empty, _ := util.NewPaths(nil)
+	if _, _, _, _, err := empty.BoundingBox(); err == nil {
+		t.Error("Expected an error for an empty Paths object")
+	}
+}
+
+func TestPathContainsPoint(t *testing.T) {
+	square := newTestPath(t, "M 0,0 L 10,0 L 10,10 L 0,10 Z")
+	cases := []struct {
+		pt   *util.Point
+		want bool
+	}{
+		{util.NewPoint(5, 5), true},
+		{util.NewPoint(0.5, 9.5), true},
+		{util.NewPoint(15, 5), false},
+		{util.NewPoint(-1, 5), false},
+		{util.NewPoint(5, 11), false},
+	}
+	for _, c := range cases {
+		if got := square.ContainsPoint(c.pt); got != c.want {
+			t.Errorf("ContainsPoint(%g,%g) = %v, want %v", c.pt.X, c.pt.Y, got, c.want)
+		}
+	}
+
+	// the same outline without the closing command encloses nothing
+	open := newTestPath(t, "M 0,0 L 10,0 L 10,10 L 0,10")
+	if open.ContainsPoint(util.NewPoint(5, 5)) {
+		t.Error("Expected an open path not to contain any point")
+	}
+}
+
+func TestPathsIntersect(t *testing.T) {
+	a := newTestPath(t, "M 0,0 L 10,10")
+	b := newTestPath(t, "M 0,10 L 10,0")
+	c := newTestPath(t, "M 0,20 L 10,20")
+	if !util.PathsIntersect(a, b) {
+		t.Error("Expected crossing lines to intersect")
+	}
+	if util.PathsIntersect(a, c) {
+		t.Error("Expected separate lines not to intersect")
+	}
+
+	// a small square entirely inside a larger one doesn't cross its edges
+	outer := newTestPath(t, "M 0,0 L 10,0 L 10,10 L 0,10 Z")
+	inner := newTestPath(t, "M 4,4 L 6,4 L 6,6 L 4,6 Z")
+	if util.PathsIntersect(outer, inner) {
+		t.Error("Expected nested squares not to intersect")
+	}
+	if !util.PathsIntersect(outer, b) {
+		t.Error("Expected the diagonal to intersect the square")
+	}
+}
+
+func TestPathsIntersectIgnoresMoves(t *testing.T) {
+	// the only crossing is the pen-up move between the two lines
+	twoLines := newTestPath(t, "M 0 0 L 1 0 M 0 10 L 1 10")
+	between := newTestPath(t, "M 0 5 L 1 5")
+	if util.PathsIntersect(twoLines, between) {
+		t.Error("Expected the move between subpaths not to intersect")
+	}
+	if !util.PathsIntersect(twoLines, newTestPath(t, "M 0.5 -1 L 0.5 1")) {
+		t.Error("Expected a line crossing the first subpath to intersect")
+	}
+
+	// reversing keeps track of where the moves are
+	if err := twoLines.Reverse(); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	if util.PathsIntersect(twoLines, between) {
+		t.Error("Expected the move between reversed subpaths not to intersect")
+	}
+
+	squares := newTestPath(t, "M 0 0 L 2 0 L 2 2 L 0 2 Z M 10 10 L 12 10 L 12 12 L 10 12 Z")
+	if !squares.ContainsPoint(util.NewPoint(1, 1)) || !squares.ContainsPoint(util.NewPoint(11, 11)) {
+		t.Error("Expected a point inside either square to be contained")
+	}
+	if squares.ContainsPoint(util.NewPoint(6, 5.5)) {
+		t.Error("Expected a point between the squares not to be contained")
+	}
+}
+
+func TestPathLengthAndResampleSubpaths(t *testing.T) {
+	path := newTestPath(t, "M 0 0 L 10 0 M 100 0 L 110 0")
+	if got := path.Length(); math.Abs(got-20) > pathTolerance {
+		t.Errorf("Expected the moves not to count towards the length of 20, got %g", got)
+	}
+	if got := newTestPath(t, "M 0 0 m 5 5").Length(); got != 0 {
+		t.Errorf("Expected a path of moves to have no length, got %g", got)
+	}
+
+	// no samples are placed on the move between the lines
+	points := newTestPath(t, "M 0 0 L 1 0 M 0 10 L 1 10").Resample(5)
+	assertPoints(t, points, [][2]float64{{0, 0}, {0.5, 0}, {1, 0}, {0.5, 10}, {1, 10}})
+}
+
+func TestPathLengthAndResampleLine(t *testing.T) {
+	path := newTestPath(t, "M 0,0 L 3,4 L 3,10")
+	if got := path.Length(); math.Abs(got-11) > pathTolerance {
+		t.Errorf("Expected length 11, got %g", got)
+	}
+	points := path.Resample(12)
+	if len(points) != 12 {
+		t.Fatalf("Expected 12 points, got %d", len(points))
+	}
+	// each sample is one unit further along the path
+	assertPoints(t, points[:6], [][2]float64{{0, 0}, {0.6, 0.8}, {1.2, 1.6}, {1.8, 2.4}, {2.4, 3.2}, {3, 4}})
+	assertPoints(t, points[11:], [][2]float64{{3, 10}})
+}
+
+func TestPathLengthAndResampleArc(t *testing.T) {
+	const radius = 10.0
+	path := newTestPath(t, "M 10,0 A 10 10 0 0 1 0,10")
+	want := math.Pi * radius / 2
+	if got := path.Length(); math.Abs(got-want) > 0.01 {
+		t.Errorf("Expected quarter circle length %g, got %g", want, got)
+	}
+
+	points := path.Resample(5)
+	if len(points) != 5 {
+		t.Fatalf("Expected 5 points, got %d", len(points))
+	}
+	for i, pt := range points {
+		// every sample lies on the circle, a further 22.5 degrees round
+		if r := math.Hypot(pt.X, pt.Y); math.Abs(r-radius) > 0.01 {
+			t.Errorf("Point %d: expected radius %g, got %g", i, radius, r)
+		}
+		if i > 0 {
+			gap := math.Hypot(pt.X-points[i-1].X, pt.Y-points[i-1].Y)
+			chord := 2 * radius * math.Sin(math.Pi/16)
+			if math.Abs(gap-chord) > 0.01 {
+				t.Errorf("Point %d: expected spacing %g, got %g", i, chord, gap)
+			}
+		}
+	}
+}
+
+func TestEllipticalArcRadiusCorrection(t *testing.T) {
+	start := util.Point{X: 0, Y: 0}
+	end := util.Point{X: 10, Y: 0}
+
+	// radii of 1 can't span 10 units so they are scaled up to a semicircle of radius 5
+	arc := util.NewEllipticalArc(start, end, 1, 1, 0, true, false)
+	if math.Abs(arc.RadiusX-5) > pathTolerance || math.Abs(arc.RadiusY-5) > pathTolerance {
+		t.Fatalf("Expected radii to be scaled to 5, got %g,%g", arc.RadiusX, arc.RadiusY)
+	}
+	points := arc.GeneratePoints(9)
+	for i, pt := range points {
+		if r := math.Hypot(pt.X-5, pt.Y); math.Abs(r-5) > 1e-3 {
+			t.Errorf("Point %d (%g,%g) is not on the corrected circle", i, pt.X, pt.Y)
+		}
+	}
+	last := points[len(points)-1]
+	if math.Abs(last.X-end.X) > 1e-3 || math.Abs(last.Y-end.Y) > 1e-3 {
+		t.Errorf("Expected the arc to finish at (10,0), got (%g,%g)", last.X, last.Y)
+	}
+
+	// negative radii are used as their absolute values
+	negative := util.NewEllipticalArc(start, end, -6, -6, 0, true, false)
+	if negative.RadiusX != 6 || negative.RadiusY != 6 {
+		t.Errorf("Expected negative radii to be made absolute, got %g,%g", negative.RadiusX, negative.RadiusY)
+	}
+
+	// elliptical radii keep their proportions when scaled
+	rotated := util.NewEllipticalArc(start, end, 1, 2, math.Pi/2, true, false)
+	if math.Abs(rotated.RadiusY-2*rotated.RadiusX) > pathTolerance || math.Abs(rotated.RadiusY-5) > pathTolerance {
+		t.Errorf("Expected radii 2.5,5 got %g,%g", rotated.RadiusX, rotated.RadiusY)
+	}
+}
+
+func TestPathArcWithSmallRadii(t *testing.T) {
+	path := newTestPath(t, "M 0,0 A 0.5 0.5 0 0 1 10,0")
+	_, minY, maxX, maxY, err := path.BoundingBox()
+	if err != nil {
+		t.Fatalf("BoundingBox failed: %v", err)
+	}
+	// the corrected arc is a semicircle bulging 5 units to one side of the chord
+	if math.Abs(maxX-10) > 0.01 || math.Abs(math.Max(maxY, -minY)-5) > 0.01 {
+		t.Errorf("Expected a semicircle of radius 5, got maxX=%g minY=%g maxY=%g", maxX, minY, maxY)
+	}
+}
+
+func TestArcFinishPoint(t *testing.T) {
+	prev, err := util.NewPathCommand("M 10,10")
+	if err != nil {
+		t.Fatalf("Failed to parse move: %v", err)
+	}
+	cases := []struct {
+		cmd  string
+		want [2]float64
+	}{
+		{"A 5 5 0 0 1 20,10", [2]float64{20, 10}},
+		{"a 5 5 0 1 0 -3,4", [2]float64{7, 14}},
+	}
+	for _, c := range cases {
+		cmd, err := util.NewPathCommand(c.cmd)
+		if err != nil {
+			t.Fatalf("Failed to parse '%s': %v", c.cmd, err)
+		}
+		got, err := cmd.GetFinishPoint(prev)
+		if err != nil {
+			t.Fatalf("GetFinishPoint for '%s' failed: %v", c.cmd, err)
+		}
+		assertPoints(t, []*util.Point{got}, [][2]float64{c.want})
+	}
+}
+
+func TestVerticalFinishPoint(t *testing.T) {
+	prev, err := util.NewPathCommand("M 3,7")
+	if err != nil {
+		t.Fatalf("Failed to parse move: %v", err)
+	}
+	cases := []struct {
+		cmd  string
+		want [2]float64
+	}{
+		{"V 12", [2]float64{3, 12}},
+		{"v -5", [2]float64{3, 2}},
+	}
+	for _, c := range cases {
+		cmd, err := util.NewPathCommand(c.cmd)
+		if err != nil {
+			t.Fatalf("Failed to parse '%s': %v", c.cmd, err)
+		}
+		got, err := cmd.GetFinishPoint(prev)
+		if err != nil {
+			t.Fatalf("GetFinishPoint for '%s' failed: %v", c.cmd, err)
+		}
+		assertPoints(t, []*util.Point{got}, [][2]float64{c.want})
+	}
+
+	// vertical lines following other commands stay at the current X
+	path := newTestPath(t, "M 1,2 V 5 L 4,5 v -3")
+	if err := path.Pointalise(10); err != nil {
+		t.Fatalf("Pointalise failed: %v", err)
+	}
+	assertPoints(t, path.Points, [][2]float64{{1, 2}, {1, 5}, {4, 5}, {4, 2}})
+}
+
+func TestHorizontalFinishPoint(t *testing.T) {
+	prev, err := util.NewPathCommand("M 3,7")
+	if err != nil {
+		t.Fatalf("Failed to parse move: %v", err)
+	}
+	cases := []struct {
+		cmd  string
+		want [2]float64
+	}{
+		{"H 12", [2]float64{12, 7}},
+		{"h -5", [2]float64{-2, 7}},
+	}
+	for _, c := range cases {
+		cmd, err := util.NewPathCommand(c.cmd)
+		if err != nil {
+			t.Fatalf("Failed to parse '%s': %v", c.cmd, err)
+		}
+		got, err := cmd.GetFinishPoint(prev)
+		if err != nil {
+			t.Fatalf("GetFinishPoint for '%s' failed: %v", c.cmd, err)
+		}
+		assertPoints(t, []*util.Point{got}, [][2]float64{c.want})
+	}
+
+	// horizontal lines following other commands stay at the current Y
+	path := newTestPath(t, "M 1,2 H 5 L 5,4 h -4")
+	if err := path.Pointalise(10); err != nil {
+		t.Fatalf("Pointalise failed: %v", err)
+	}
+	assertPoints(t, path.Points, [][2]float64{{1, 2}, {5, 2}, {5, 4}, {1, 4}})
+}
+
+func TestPathToAbsolute(t *testing.T) {
+	d := "m 1,2 l 3,0 h 2 v 4 q 1,1 2,0 a 1 1 0 0 1 -2,0 z l 1,1 M 10,10 h -1 V 3 z"
+	path := newTestPath(t, d)
+
+	// the finish point of every command must be unchanged
+	finishPoints := func(p *util.Path) [][2]float64 {
+		current := util.NewPoint(0, 0)
+		start := current
+		var points [][2]float64
+		for _, cmd := range p.Commands {
+			if cmd.Letter == "Z" || cmd.Letter == "z" {
+				current = start
+			} else {
+				finish, err := cmd.GetFinishPoint(&util.PathCommand{Letter: "M", Params: []float64{current.X, current.Y}})
+				if err != nil {
+					t.Fatalf("GetFinishPoint for %s failed: %v", cmd.Letter, err)
+				}
+				current = finish
+				if cmd.Letter == "M" || cmd.Letter == "m" {
+					start = current
+				}
+			}
+			points = append(points, [2]float64{current.X, current.Y})
+		}
+		return points
+	}
+	want := finishPoints(path)
+
+	if err := path.ToAbsolute(); err != nil {
+		t.Fatalf("ToAbsolute failed: %v", err)
+	}
+	for _, cmd := range path.Commands {
+		if util.StringIsLower(cmd.Letter) {
+			t.Errorf("Expected only absolute commands, found %s", cmd.Letter)
+		}
+	}
+	got := make([]*util.Point, 0, len(want))
+	for _, p := range finishPoints(path) {
+		got = append(got, util.NewPoint(p[0], p[1]))
+	}
+	assertPoints(t, got, want)
+
+	expected := "M 1 2 L 4 2 H 6 V 6 Q 7 7 8 6 A 1 1 0 0 1 6 6 Z L 2 3 M 10 10 H 9 V 3 Z"
+	if path.CommandsStr != expected {
+		t.Errorf("Expected CommandsStr '%s', got '%s'", expected, path.CommandsStr)
+	}
+	tag, err := path.ToPathTag()
+	if err != nil || !strings.Contains(tag, expected) {
+		t.Errorf("Expected the path tag to use the absolute commands, got '%s', %v", tag, err)
+	}
+
+	// the absolute path covers the same ground as the original
+	original := newTestPath(t, d)
+	if math.Abs(original.Length()-path.Length()) > pathTolerance {
+		t.Errorf("Expected the same length, got %g and %g", original.Length(), path.Length())
+	}
+}
+
+func TestParsePathCommandsCompact(t *testing.T) {
+	cases := []struct {
+		d    string
+		want []string
+	}{
+		// numbers packed together using signs and decimal points as separators
+		{"M10-20L30.5.5", []string{"M 10 -20", "L 30.5 0.5"}},
+		// repeated coordinates after a move are implicit lines
+		{"M0 0 10 0 10 10z", []string{"M 0 0", "L 10 0", "L 10 10", "z"}},
+		{"m1,1 2,0 0,2", []string{"m 1 1", "l 2 0", "l 0 2"}},
+		// repeated parameter sets for other commands repeat the command
+		{"M0,0H5 10V3-3", []string{"M 0 0", "H 5", "H 10", "V 3", "V -3"}},
+		// arc flags packed against each other and the end point
+		{"M0,0a5,5 0 1010,0", []string{"M 0 0", "a 5 5 0 1 0 10 0"}},
+		{"M0,0A5 5 30 0110-2.5e1", []string{"M 0 0", "A 5 5 30 0 1 10 -25"}},
+		// exponents
+		{"M1e1,2E-1", []string{"M 10 0.2"}},
+	}
+	for _, c := range cases {
+		path := &util.Path{CommandsStr: c.d}
+		if err := path.ParsePathCommands(); err != nil {
+			t.Errorf("Failed to parse '%s': %v", c.d, err)
+			continue
+		}
+		got := make([]string, len(path.Commands))
+		for i, cmd := range path.Commands {
+			got[i] = cmd.Letter
+			for _, v := range cmd.Params {
+				got[i] += " " + strconv.FormatFloat(v, 'g', -1, 64)
+			}
+		}
+		if strings.Join(got, "|") != strings.Join(c.want, "|") {
+			t.Errorf("Parsing '%s': expected %v, got %v", c.d, c.want, got)
+		}
+	}
+}
+
+func TestParsePathCommandsInvalid(t *testing.T) {
+	for _, d := range []string{"M 1", "M 1 2 L", "M 1 2 X 3", "M0,0 A5 5 0 2 1 10 0", "M 1 2 3"} {
+		path := &util.Path{CommandsStr: d}
+		if err := path.ParsePathCommands(); err == nil {
+			t.Errorf("Expected an error parsing '%s'", d)
+		}
+	}
+}
+
+func TestParsePathCommandsUnsupported(t *testing.T) {
+	cases := map[string]string{
+		"M0 0 C1 1 2 2 3 3":  "command C (cubic Bézier curve) at position 5",
+		"M0 0 c1 1 2 2 3 3":  "command c (cubic Bézier curve)",
+		"M0 0 S1 1 2 2":      "command S (smooth cubic Bézier curve)",
+		"M0 0 Q1 1 2 2 t3 3": "command t (smooth quadratic Bézier curve)",
+	}
+	for d, expected := range cases {
+		path := &util.Path{CommandsStr: d}
+		err := path.ParsePathCommands()
+		if err == nil || !strings.Contains(err.Error(), expected) || !strings.Contains(err.Error(), "not currently supported") {
+			t.Errorf("Expected parsing '%s' to be rejected with %q, got %v", d, expected, err)
+		}
+	}
+}
+
+func TestTextToPaths(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0644); err != nil {
+		t.Fatalf("Failed to write font: %v", err)
+	}
+
+	// the space has no outline so only two glyphs are produced
+	paths, err := util.TextToPaths("H i", fontPath, 10)
+	if err != nil {
+		t.Fatalf("TextToPaths failed: %v", err)
+	}
+	if paths.NumPaths() != 2 {
+		t.Fatalf("Expected 2 glyph paths, got %d", paths.NumPaths())
+	}
+	for _, p := range paths.Paths {
+		if !p.IsClosed || len(p.Commands) == 0 {
+			t.Errorf("Expected glyph %s to be a closed outline", p.ID)
+		}
+	}
+
+	hMinX, hMinY, hMaxX, hMaxY, err := paths.Paths[0].BoundingBox()
+	if err != nil {
+		t.Fatalf("BoundingBox failed: %v", err)
+	}
+	iMinX, _, _, _, err := paths.Paths[1].BoundingBox()
+	if err != nil {
+		t.Fatalf("BoundingBox failed: %v", err)
+	}
+	// glyphs sit on the baseline (y=0) extending upwards and are laid out left to right
+	if hMaxY > 0.01 || hMinY > -5 || hMaxX-hMinX > 10 {
+		t.Errorf("Unexpected extents for H: %g,%g %g,%g", hMinX, hMinY, hMaxX, hMaxY)
+	}
+	if iMinX <= hMaxX {
+		t.Errorf("Expected i (minX %g) to be placed after H (maxX %g)", iMinX, hMaxX)
+	}
+
+	// characters missing from the font are skipped
+	paths, err = util.TextToPaths("H\U0001F600", fontPath, 10)
+	if err != nil {
+		t.Fatalf("TextToPaths failed with a missing glyph: %v", err)
+	}
+	if paths.NumPaths() != 1 {
+		t.Errorf("Expected the missing glyph to be skipped, got %d paths", paths.NumPaths())
+	}
+
+	if _, err := util.TextToPaths("H", filepath.Join(t.TempDir(), "missing.ttf"), 10); err == nil {
+		t.Error("Expected an error for a missing font")
+	}
+}
+
+// parseGCodeLine splits a GCode line such as 'G01 X1 Y2' into its code and parameter values
+func parseGCodeLine(t *testing.T, line string) (string, map[string]float64) {
+	t.Helper()
+	words := strings.Fields(line)
+	params := map[string]float64{}
+	for _, w := range words[1:] {
+		v, err := strconv.ParseFloat(w[1:], 64)
+		if err != nil {
+			t.Fatalf("Invalid parameter '%s' in '%s'", w, line)
+		}
+		params[w[:1]] = v
+	}
+	return words[0], params
+}
+
+func TestCubicToQuadratics(t *testing.T) {
+	cubicAt := func(p0, c1, c2, p3 util.Point, u float64) util.Point {
+		a, b, c, d := (1-u)*(1-u)*(1-u), 3*(1-u)*(1-u)*u, 3*(1-u)*u*u, u*u*u
+		return util.Point{X: a*p0.X + b*c1.X + c*c2.X + d*p3.X, Y: a*p0.Y + b*c1.Y + c*c2.Y + d*p3.Y}
+	}
+
+	// an S shaped cubic can't be matched by a single quadratic
+	p0, c1, c2, p3 := util.Point{X: 0, Y: 0}, util.Point{X: 10, Y: -10}, util.Point{X: -10, Y: -10}, util.Point{X: 0, Y: -20}
+	tolerance := 0.01
+	quads := util.CubicToQuadratics(p0, c1, c2, p3, tolerance)
+	if len(quads) < 2 {
+		t.Fatalf("Expected the cubic to be split into several quadratics, got %d", len(quads))
+	}
+	if last := quads[len(quads)-1][1]; math.Abs(last.X-p3.X) > pathTolerance || math.Abs(last.Y-p3.Y) > pathTolerance {
+		t.Errorf("Expected the quadratics to end at the cubic's end point, got %v", last)
+	}
+
+	// sample the quadratics densely and check every point of the cubic is close to them
+	var samples []util.Point
+	start := p0
+	for _, q := range quads {
+		for k := 0; k <= 2000; k++ {
+			u := float64(k) / 2000
+			a, b, c := (1-u)*(1-u), 2*(1-u)*u, u*u
+			samples = append(samples, util.Point{X: a*start.X + b*q[0].X + c*q[1].X, Y: a*start.Y + b*q[0].Y + c*q[1].Y})
+		}
+		start = q[1]
+	}
+	for k := 0; k <= 500; k++ {
+		pt := cubicAt(p0, c1, c2, p3, float64(k)/500)
+		nearest := math.Inf(1)
+		for _, s := range samples {
+			nearest = math.Min(nearest, math.Hypot(pt.X-s.X, pt.Y-s.Y))
+		}
+		if nearest > tolerance {
+			t.Fatalf("Cubic point %v is %g from the quadratics, more than the tolerance %g", pt, nearest, tolerance)
+		}
+	}
+
+	// a cubic which is really a quadratic is kept as that one quadratic
+	quads = util.CubicToQuadratics(util.Point{X: 0, Y: 0}, util.Point{X: 2, Y: 4}, util.Point{X: 4, Y: 4}, util.Point{X: 6, Y: 0}, tolerance)
+	if len(quads) != 1 || math.Abs(quads[0][0].X-3) > pathTolerance || math.Abs(quads[0][0].Y-6) > pathTolerance {
+		t.Errorf("Expected a single quadratic with control 3,6, got %v", quads)
+	}
+}
+
+func TestPathToGCodeArcs(t *testing.T) {
+	cases := []struct {
+		d        string
+		code     string
+		i, j     float64
+		endX     float64
+		endY     float64
+		startIdx int
+	}{
+		// quarter circle about the origin
+		{"M 10,0 A 10 10 0 0 1 0,10", "G03", -10, 0, 0, 10, 1},
+		// the same end points the other way round the circle centred at (10,10)
+		{"M 10,0 a 10 10 0 0 0 -10,10", "G02", 0, 10, 0, 10, 1},
+		// radii too small are scaled up to a semicircle centred halfway along the chord
+		{"M 0,0 L 2,0 A 1 1 0 0 0 6,0", "G02", 2, 0, 6, 0, 2},
+	}
+	for _, c := range cases {
+		path := newTestPath(t, c.d)
+		gcode, err := path.ToGCodeWithOptions(util.GCodeOptions{UseArcs: true})
+		if err != nil {
+			t.Fatalf("ToGCode failed for '%s': %v", c.d, err)
+		}
+		lines := strings.Split(gcode, "\n")
+		if len(lines) != c.startIdx+1 {
+			t.Fatalf("Expected %d lines for '%s', got:\n%s", c.startIdx+1, c.d, gcode)
+		}
+		code, params := parseGCodeLine(t, lines[c.startIdx])
+		if code != c.code {
+			t.Errorf("Expected %s for '%s', got %s", c.code, c.d, code)
+		}
+		want := map[string]float64{"X": c.endX, "Y": c.endY, "I": c.i, "J": c.j}
+		for k, v := range want {
+			if math.Abs(params[k]-v) > 1e-3 {
+				t.Errorf("Expected %s%g for '%s', got %s", k, v, c.d, lines[c.startIdx])
+			}
+		}
+	}
+}
+
+func TestPathToGCodeEllipseFallback(t *testing.T) {
+	path := newTestPath(t, "M 10,0 A 10 5 0 0 1 0,5")
+	gcode, err := path.ToGCodeWithOptions(util.GCodeOptions{UseArcs: true, MaxPointDistance: 1})
+	if err != nil {
+		t.Fatalf("ToGCode failed: %v", err)
+	}
+	lines := strings.Split(gcode, "\n")
+	if len(lines) < 5 {
+		t.Fatalf("Expected the ellipse to be linearised, got:\n%s", gcode)
+	}
+	for _, line := range lines[1:] {
+		if code, _ := parseGCodeLine(t, line); code != "G01" {
+			t.Errorf("Expected only G01 moves for an ellipse, got %s", line)
+		}
+	}
+}
+
+func TestGCodeString(t *testing.T) {
+	// parameters are written in the canonical order whatever order they were given in
+	g := &util.GCode{Letter: "G01", Params: []util.GCodeParameter{{Letter: "Y", Value: 2}, {Letter: "X", Value: 5.38712}}}
+	cases := []struct {
+		format util.GCodeFormat
+		want   string
+	}{
+		{util.GCodeFormat{Precision: 3, TrimZeros: true}, "G01 X5.387 Y2"},
+		{util.GCodeFormat{Precision: 3}, "G01 X5.387 Y2.000"},
+		{util.GCodeFormat{Precision: 0}, "G01 X5 Y2"},
+	}
+	for _, c := range cases {
+		if got := g.Format(c.format); got != c.want {
+			t.Errorf("Format(%+v) = '%s', want '%s'", c.format, got, c.want)
+		}
+	}
+	if got := g.String(); got != "G01 X5.3871 Y2" {
+		t.Errorf("String() = '%s', want 'G01 X5.3871 Y2'", got)
+	}
+
+	p := &util.GCodeParameter{Letter: "X", Value: -0.00001}
+	if got := p.Format(util.GCodeFormat{Precision: 3, TrimZeros: true}); got != "X0" {
+		t.Errorf("Expected a value rounding to zero to be written as X0, got %s", got)
+	}
+	if got := (&util.GCode{Letter: "M30"}).String(); got != "M30" {
+		t.Errorf("Expected a command without parameters to be written alone, got '%s'", got)
+	}
+}
+
+func TestPathsOptimizeTravelOrder(t *testing.T) {
+	line := func(id string, x0, y0, x1, y1 float64) *util.Path {
+		p, err := util.NewPathFromPoints([]*util.Point{util.NewPoint(x0, y0), util.NewPoint(x1, y1)}, id)
+		if err != nil {
+			t.Fatalf("Failed to create path: %v", err)
+		}
+		return p
+	}
+	// deliberately ordered to zig-zag back and forth
+	paths, _ := util.NewPaths([]*util.Path{
+		line("far", 50, 0, 60, 0),
+		line("near", 0, 0, 10, 0),
+		line("middle", 30, 0, 20, 0),
+		line("farther", 100, 0, 90, 0),
+	})
+
+	before, after, err := paths.OptimizeTravelOrder(true)
+	if err != nil {
+		t.Fatalf("OptimizeTravelOrder failed: %v", err)
+	}
+	if after > before {
+		t.Errorf("Expected optimised travel %g to be no greater than %g", after, before)
+	}
+	travel, err := paths.TravelDistance()
+	if err != nil {
+		t.Fatalf("TravelDistance failed: %v", err)
+	}
+	if math.Abs(travel-after) > pathTolerance {
+		t.Errorf("Expected reported travel %g to match the new order's travel %g", after, travel)
+	}
+
+	ids := []string{}
+	for _, p := range paths.Paths {
+		ids = append(ids, p.ID)
+	}
+	if strings.Join(ids, ",") != "near,middle,far,farther" {
+		t.Errorf("Unexpected order %v", ids)
+	}
+	// the middle path was reversed so it runs away from the origin
+	if paths.Paths[1].Points[0].X != 20 {
+		t.Errorf("Expected the middle path to be reversed to start at 20, got %g", paths.Paths[1].Points[0].X)
+	}
+
+	// without reversing the order still mustn't get worse
+	paths, _ = util.NewPaths([]*util.Path{line("b", 10, 0, 0, 0), line("a", 0, 0, 10, 0)})
+	before, after, err = paths.OptimizeTravelOrder(false)
+	if err != nil {
+		t.Fatalf("OptimizeTravelOrder failed: %v", err)
+	}
+	if after > before {
+		t.Errorf("Expected optimised travel %g to be no greater than %g", after, before)
+	}
+}
+
+func TestPathsOptimizeTravelOrderKeepsSubpaths(t *testing.T) {
+	near, err := util.NewPathFromPoints([]*util.Point{util.NewPoint(0, 0), util.NewPoint(10, 0)}, "near")
+	if err != nil {
+		t.Fatalf("Failed to create path: %v", err)
+	}
+	// two strokes, finishing with a curve back towards the near path
+	strokes := newTestPath(t, "M 50,0 L 60,0 M 40,10 Q 30,20 20,10")
+	paths, _ := util.NewPaths([]*util.Path{near, strokes})
+
+	before, after, err := paths.OptimizeTravelOrder(true)
+	if err != nil {
+		t.Fatalf("OptimizeTravelOrder failed: %v", err)
+	}
+	if after >= before {
+		t.Fatalf("Expected reversing the strokes to reduce travel from %g, got %g", before, after)
+	}
+	// the strokes are reversed as separate subpaths rather than joined into one line
+	expected := "M 20 10 Q 30 20 40 10 M 60 0 L 50 0"
+	if strokes.CommandsStr != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, strokes.CommandsStr)
+	}
+	travel, err := paths.TravelDistance()
+	if err != nil {
+		t.Fatalf("TravelDistance failed: %v", err)
+	}
+	if math.Abs(travel-after) > pathTolerance {
+		t.Errorf("Expected reported travel %g to match the new order's travel %g", after, travel)
+	}
+}
+
+func TestGenerateGCodeProgram(t *testing.T) {
+	square := newTestPath(t, "M 0,0 L 10,0 L 10,10 Z")
+	paths, _ := util.NewPaths([]*util.Path{square})
+
+	program, err := util.GenerateGCodeProgram(paths, util.GCodeOptions{
+		Units:        "mm",
+		FeedRate:     300,
+		SafeZ:        5,
+		CutZ:         -1,
+		SpindleSpeed: 10000,
+	})
+	if err != nil {
+		t.Fatalf("GenerateGCodeProgram failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(program), "\n")
+	want := []string{"G21", "G90", "M03 S10000", "G00 Z5", "G00 X0 Y0", "G01 Z-1 F300", "G01 X10 Y0", "G01 X10 Y10", "G01 X0 Y0", "G00 Z5", "M05", "G00 X0 Y0", "M30"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected program:\n%s", program)
+	}
+
+	program, err = util.GenerateGCodeProgram(paths, util.GCodeOptions{
+		Units:     "inch",
+		FeedRate:  10,
+		SafeZ:     0.25,
+		Preamble:  []string{"(custom)", "M03 S500"},
+		Postamble: []string{"M05"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateGCodeProgram failed: %v", err)
+	}
+	if !strings.HasPrefix(program, "G20\nG90\n(custom)\nM03 S500\n") {
+		t.Errorf("Expected the program to start with the custom preamble, got:\n%s", program)
+	}
+	if !strings.HasSuffix(program, "M05\nM30\n") {
+		t.Errorf("Expected the program to end with the postamble and M30, got:\n%s", program)
+	}
+}
+
+func TestGenerateGCodeProgramInvalid(t *testing.T) {
+	paths, _ := util.NewPaths([]*util.Path{newTestPath(t, "M 0,0 L 1,1")})
+	for _, opts := range []util.GCodeOptions{
+		{FeedRate: 0, SafeZ: 5},
+		{FeedRate: 100, SafeZ: 0},
+		{FeedRate: 100, SafeZ: -2},
+		{FeedRate: 100, SafeZ: 5, Units: "cubits"},
+	} {
+		if _, err := util.GenerateGCodeProgram(paths, opts); err == nil {
+			t.Errorf("Expected an error for options %+v", opts)
+		}
+	}
+}
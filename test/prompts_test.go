@@ -0,0 +1,209 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richard-senior/mcp/pkg/prompts"
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/tools"
+)
+
+// waitForPrompts waits for the next list of prompts reported by a watcher
+func waitForPrompts(t *testing.T, changes chan []protocol.Prompt) []protocol.Prompt {
+	t.Helper()
+	select {
+	case list := <-changes:
+		return list
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for prompts to reload")
+		return nil
+	}
+}
+
+// greetingPrompt creates a valid prompt using the name variable
+func greetingPrompt(content string) *protocol.Prompt {
+	return &protocol.Prompt{
+		ID:        "greeting",
+		Content:   content,
+		Variables: map[string]protocol.PromptArgument{"name": {Required: true}},
+	}
+}
+
+func TestPromptWatcherReloads(t *testing.T) {
+	dir := t.TempDir()
+	registry := prompts.NewPromptRegistryWithDir(dir)
+	if err := registry.SavePrompt(greetingPrompt("Hello {{name}}")); err != nil {
+		t.Fatalf("Failed to save prompt: %v", err)
+	}
+
+	changes := make(chan []protocol.Prompt, 10)
+	watcher, err := registry.Watch(50*time.Millisecond, func(list []protocol.Prompt) {
+		changes <- list
+	})
+	if err != nil {
+		t.Fatalf("Failed to watch prompts: %v", err)
+	}
+	defer watcher.Close()
+
+	// several rapid edits are reported as a single reload of the final version
+	for _, content := range []string{"Hi {{name}}", "Hey {{name}}", "Howdy {{name}}"} {
+		if err := registry.SavePrompt(greetingPrompt(content)); err != nil {
+			t.Fatalf("Failed to save prompt: %v", err)
+		}
+	}
+	list := waitForPrompts(t, changes)
+	if len(list) != 1 || list[0].Content != "Howdy {{name}}" {
+		t.Fatalf("Expected the latest version of the prompt, got %+v", list)
+	}
+	select {
+	case extra := <-changes:
+		t.Errorf("Expected rapid edits to be debounced, got an extra reload %+v", extra)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// a broken file keeps the previous version so nothing is reported
+	if err := os.WriteFile(filepath.Join(dir, "greeting.json"), []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write prompt: %v", err)
+	}
+	select {
+	case list := <-changes:
+		t.Errorf("Expected a broken prompt not to be reloaded, got %+v", list)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// new prompts are added alongside the retained version
+	if err := registry.SavePrompt(&protocol.Prompt{ID: "farewell", Content: "Bye"}); err != nil {
+		t.Fatalf("Failed to save prompt: %v", err)
+	}
+	list = waitForPrompts(t, changes)
+	if len(list) != 2 || list[0].ID != "farewell" || list[1].Content != "Howdy {{name}}" {
+		t.Errorf("Expected the new prompt and the retained version, got %+v", list)
+	}
+
+	// deleted prompts are removed
+	if err := registry.DeletePrompt("farewell"); err != nil {
+		t.Fatalf("Failed to delete prompt: %v", err)
+	}
+	list = waitForPrompts(t, changes)
+	if len(list) != 1 || list[0].ID != "greeting" {
+		t.Errorf("Expected the deleted prompt to be removed, got %+v", list)
+	}
+}
+
+func TestValidatePrompt(t *testing.T) {
+	vars := map[string]protocol.PromptArgument{"name": {Required: true}, "place": {}}
+	cases := []struct {
+		content string
+		want    error
+		where   string
+	}{
+		{"Hello {{name}}, welcome to {{place}}.\nUse {braces} freely", nil, ""},
+		{"Hello {{name}", prompts.ErrUnclosedPlaceholder, "line 1 column 7"},
+		{"Hello {{name {{place}}", prompts.ErrUnclosedPlaceholder, "line 1 column 7"},
+		{"Hello name}} and {{place}}", prompts.ErrUnopenedPlaceholder, "line 1 column 11"},
+		{"{{name}}\nthen}}", prompts.ErrUnopenedPlaceholder, "line 2 column 5"},
+		{"Hello {{ name }}", prompts.ErrInvalidPlaceholder, "line 1 column 7"},
+		{"Hello {{}}", prompts.ErrInvalidPlaceholder, "line 1 column 7"},
+		{"Hello {{name}}\n  from {{town}}", prompts.ErrUndeclaredVariable, "line 2 column 8"},
+	}
+	for _, c := range cases {
+		err := prompts.ValidatePrompt(protocol.Prompt{ID: "test", Content: c.content, Variables: vars})
+		if c.want == nil {
+			if err != nil {
+				t.Errorf("Expected %q to be valid, got %v", c.content, err)
+			}
+			continue
+		}
+		if !errors.Is(err, c.want) {
+			t.Errorf("Expected %q to fail with %v, got %v", c.content, c.want, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.where) {
+			t.Errorf("Expected the error for %q to give the position %s, got %v", c.content, c.where, err)
+		}
+	}
+}
+
+func TestRegistryRejectsInvalidPrompts(t *testing.T) {
+	dir := t.TempDir()
+	registry := prompts.NewPromptRegistryWithDir(dir)
+	invalid := `{"id":"broken","content":"Hello {{name}}","variables":{}}`
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(invalid), 0644); err != nil {
+		t.Fatalf("Failed to write prompt: %v", err)
+	}
+	if err := registry.SavePrompt(&protocol.Prompt{ID: "ok", Content: "Hi"}); err != nil {
+		t.Fatalf("Failed to save prompt: %v", err)
+	}
+
+	if _, err := registry.GetPrompt("broken"); !errors.Is(err, prompts.ErrUndeclaredVariable) {
+		t.Errorf("Expected loading an invalid prompt to fail validation, got %v", err)
+	}
+	list, err := registry.ListPrompts()
+	if err != nil {
+		t.Fatalf("ListPrompts failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != "ok" {
+		t.Errorf("Expected only the valid prompt to be listed, got %+v", list)
+	}
+}
+
+func TestSamplePromptsKeepUserEdits(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".mcp", "prompts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create prompts directory: %v", err)
+	}
+	// an edit which no longer validates, as lang isn't declared
+	edited := `{"id":"code-review","content":"Review this {{lang}} code","variables":{}}`
+	path := filepath.Join(dir, "code-review.json")
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to write prompt: %v", err)
+	}
+
+	prompts.NewPromptRegistry()
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != edited {
+		t.Errorf("Expected the edited prompt to be kept, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sample.json")); err != nil {
+		t.Errorf("Expected missing sample prompts to be created: %v", err)
+	}
+}
+
+func TestRenderPrompt(t *testing.T) {
+	prompt := protocol.Prompt{
+		ID:      "review",
+		Content: "Review this {{language}} code:\n{{code}}\nFocus on {{focus}} in {{language}}",
+		Variables: map[string]protocol.PromptArgument{
+			"language": {Required: true},
+			"code":     {Required: true},
+			"focus":    {},
+		},
+	}
+
+	content, unsubstituted := prompts.RenderPrompt(prompt, map[string]string{"language": "go"})
+	if content != "Review this go code:\n{{code}}\nFocus on {{focus}} in go" {
+		t.Errorf("Unexpected content %q", content)
+	}
+	if strings.Join(unsubstituted, ",") != "code,focus" {
+		t.Errorf("Expected code and focus to be unsubstituted, got %v", unsubstituted)
+	}
+
+	preview := tools.PreviewPrompt(prompt, map[string]string{"focus": "errors"})
+	if missing := preview["missingRequired"].([]string); strings.Join(missing, ",") != "code,language" {
+		t.Errorf("Expected code and language to be reported missing, got %v", missing)
+	}
+
+	all := map[string]string{"language": "go", "code": "x := 1", "focus": "style"}
+	preview = tools.PreviewPrompt(prompt, all)
+	if len(preview["unsubstituted"].([]string)) != 0 || len(preview["missingRequired"].([]string)) != 0 {
+		t.Errorf("Expected a fully substituted prompt, got %+v", preview)
+	}
+}
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/tools"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	results := tools.RunSelfTest([]tools.SelfTestCheck{
+		{Name: "up", Required: true, Check: func() error { return nil }},
+		{Name: "optional", Check: func() error { return errors.New("no route") }},
+	})
+	if len(results) != 2 || results[0].Name != "up" || results[1].Name != "optional" {
+		t.Fatalf("Expected a result per check in order, got %+v", results)
+	}
+	if !results[0].OK || results[0].Error != "" {
+		t.Errorf("Expected the first check to pass, got %+v", results[0])
+	}
+	if results[1].OK || results[1].Error != "no route" {
+		t.Errorf("Expected the second check to fail with its error, got %+v", results[1])
+	}
+	if !tools.SelfTestPassed(results) {
+		t.Error("Expected a failing optional check not to fail the self test")
+	}
+
+	var report bytes.Buffer
+	tools.WriteSelfTestReport(&report, results)
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "OK") || !strings.HasPrefix(lines[1], "WARN") || !strings.Contains(lines[1], "no route") {
+		t.Errorf("Unexpected report:\n%s", report.String())
+	}
+}
+
+func TestSelfTestFailsOnRequiredCheck(t *testing.T) {
+	results := tools.RunSelfTest([]tools.SelfTestCheck{
+		{Name: "down", Required: true, Check: func() error { return errors.New("timeout") }},
+	})
+	if tools.SelfTestPassed(results) {
+		t.Error("Expected a failing required check to fail the self test")
+	}
+}
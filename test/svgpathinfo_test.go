@@ -0,0 +1,51 @@
+package test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/tools"
+)
+
+func TestSvgPathInfo(t *testing.T) {
+	info := tools.GetSvgPathInfo("M 0,0 L 10,0 V 10 h -10 Z", 0.5)
+	if !info.Valid || info.Error != "" {
+		t.Fatalf("Expected a valid path, got %+v", info)
+	}
+	if info.CommandCount != 5 || info.Subpaths != 1 || !info.Closed {
+		t.Errorf("Expected 5 commands in 1 closed subpath, got %+v", info)
+	}
+	if math.Abs(info.Length-40) > pathTolerance {
+		t.Errorf("Expected a length of 40, got %g", info.Length)
+	}
+	if info.MinX != 0 || info.MinY != 0 || info.MaxX != 10 || info.MaxY != 10 {
+		t.Errorf("Expected a 10x10 bounding box, got %+v", info)
+	}
+
+	tag := tools.GetSvgPathInfo(`<path id="line" d="M 1,1 l 3,4" />`, 0.5)
+	if !tag.Valid || tag.ID != "line" || tag.Closed || math.Abs(tag.Length-5) > pathTolerance {
+		t.Errorf("Expected an open line of length 5 from the tag, got %+v", tag)
+	}
+
+	moves := tools.GetSvgPathInfo("M 0 0 m 5 5", 0.5)
+	if !moves.Valid || moves.Length != 0 {
+		t.Errorf("Expected a path of moves to have no length, got %+v", moves)
+	}
+}
+
+func TestSvgPathInfoInvalid(t *testing.T) {
+	cases := map[string]string{
+		"M 0,0 L 10":            "command L",
+		"M 0,0 X 5,5":           "unexpected character 'X'",
+		"M 0,0 C 1 1 2 2 3 3":   "command C (cubic Bézier curve) at position 6 is not currently supported",
+		"M 0,0 s 1 1 2 2":       "command s (smooth cubic Bézier curve)",
+		"M 0,0 Q 1 1 2 2 T 3 3": "command T (smooth quadratic Bézier curve)",
+	}
+	for path, expected := range cases {
+		info := tools.GetSvgPathInfo(path, 0.5)
+		if info.Valid || !strings.Contains(info.Error, expected) {
+			t.Errorf("Expected '%s' to be invalid with an error mentioning %q, got %+v", path, expected, info)
+		}
+	}
+}
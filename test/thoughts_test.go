@@ -0,0 +1,91 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richard-senior/mcp/pkg/tools"
+)
+
+func TestValidateThoughtCategory(t *testing.T) {
+	t.Setenv(tools.THOUGHT_CATEGORIES_ENV, "")
+	category, err := tools.ValidateThoughtCategory(" Decision ")
+	if err != nil {
+		t.Fatalf("Expected default category to be valid: %v", err)
+	}
+	if category != "decision" {
+		t.Errorf("Expected category to be normalised to 'decision', got '%s'", category)
+	}
+	if _, err := tools.ValidateThoughtCategory("musing"); err == nil {
+		t.Error("Expected unknown category to be rejected")
+	}
+
+	t.Setenv(tools.THOUGHT_CATEGORIES_ENV, "musing, risk")
+	if _, err := tools.ValidateThoughtCategory("musing"); err != nil {
+		t.Errorf("Expected configured category to be valid: %v", err)
+	}
+	if _, err := tools.ValidateThoughtCategory("decision"); err == nil {
+		t.Error("Expected category missing from the configured list to be rejected")
+	}
+}
+
+func TestRenderThoughtsMarkdown(t *testing.T) {
+	t.Setenv(tools.THOUGHT_CATEGORIES_ENV, "")
+	start := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	thoughts := []tools.ThoughtData{
+		{Thought: "Ship it", ThoughtNumber: 3, TotalThoughts: 3, Category: "decision", Timestamp: start.Add(2 * time.Minute)},
+		{Thought: "Loose end", ThoughtNumber: 4, TotalThoughts: 4, Timestamp: start.Add(3 * time.Minute)},
+		{Thought: "Cache is stale", ThoughtNumber: 2, TotalThoughts: 3, Category: "hypothesis", Timestamp: start.Add(time.Minute), Outcomes: "confirmed"},
+		{Thought: "Maybe the network", ThoughtNumber: 1, TotalThoughts: 3, Category: "hypothesis", Timestamp: start},
+	}
+
+	md := tools.RenderThoughtsMarkdown("Thoughts", thoughts)
+
+	hypothesis := strings.Index(md, "## Hypothesis")
+	decision := strings.Index(md, "## Decision")
+	uncategorised := strings.Index(md, "## Uncategorised")
+	if hypothesis < 0 || decision < 0 || uncategorised < 0 {
+		t.Fatalf("Expected a section per category, got:\n%s", md)
+	}
+	if !(hypothesis < decision && decision < uncategorised) {
+		t.Errorf("Expected categories in configured order with uncategorised last, got:\n%s", md)
+	}
+	if strings.Index(md, "Maybe the network") > strings.Index(md, "Cache is stale") {
+		t.Errorf("Expected thoughts within a category in timestamp order, got:\n%s", md)
+	}
+	if !strings.Contains(md, "- **2025-01-02 10:01:00** (thought 2/3) Cache is stale\n  - Outcomes: confirmed\n") {
+		t.Errorf("Expected thought with timestamp and outcomes, got:\n%s", md)
+	}
+}
+
+func TestThoughtsExportIncludesUnsavedThoughts(t *testing.T) {
+	t.Setenv(tools.THOUGHT_CATEGORIES_ENV, "")
+	pwd := t.TempDir()
+	if err := os.Mkdir(filepath.Join(pwd, ".amazonq"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := tools.HandleThoughts(map[string]interface{}{
+		"pwd":               pwd,
+		"thought":           "Not saved yet",
+		"thoughtNumber":     float64(1),
+		"totalThoughts":     float64(1),
+		"nextThoughtNeeded": false,
+		"category":          "todo",
+	})
+	if err != nil {
+		t.Fatalf("HandleThoughts failed: %v", err)
+	}
+
+	result, err := tools.HandleThoughtsExport(map[string]interface{}{"pwd": pwd})
+	if err != nil {
+		t.Fatalf("HandleThoughtsExport failed: %v", err)
+	}
+	export := result.(map[string]any)
+	if export["count"] != 1 || !strings.Contains(export["markdown"].(string), "Not saved yet") {
+		t.Errorf("Expected the unsaved thought to be exported, got %v", export)
+	}
+}
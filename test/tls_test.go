@@ -0,0 +1,106 @@
+package test
+
+import (
+	"crypto/tls"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/internal/logger"
+	"github.com/richard-senior/mcp/pkg/transport"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	cases := map[string]uint16{
+		"":       tls.VersionTLS12,
+		"1.0":    tls.VersionTLS10,
+		"1.3":    tls.VersionTLS13,
+		"TLS1.2": tls.VersionTLS12,
+	}
+	for input, expected := range cases {
+		v, err := transport.ParseTLSVersion(input)
+		if err != nil || v != expected {
+			t.Errorf("ParseTLSVersion(%q) = %x, %v, expected %x", input, v, err, expected)
+		}
+	}
+	if _, err := transport.ParseTLSVersion("2.0"); err == nil {
+		t.Error("Expected an unknown TLS version to be rejected")
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	ids, err := transport.ParseCipherSuites("TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 || ids[1] != tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384 {
+		t.Errorf("Unexpected cipher suites %v", ids)
+	}
+	if ids, err := transport.ParseCipherSuites(""); err != nil || ids != nil {
+		t.Errorf("Expected no cipher suites for an empty list, got %v, %v", ids, err)
+	}
+	if _, err := transport.ParseCipherSuites("TLS_RSA_WITH_RC4_128_SHA"); err == nil {
+		t.Error("Expected an insecure cipher suite to be rejected")
+	}
+	if _, err := transport.ParseCipherSuites("NOT_A_SUITE"); err == nil {
+		t.Error("Expected an unknown cipher suite to be rejected")
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Setenv(transport.EnvTLSMinVersion, "1.3")
+	config, err := transport.NewTLSConfig(nil)
+	if err != nil || config.MinVersion != tls.VersionTLS13 {
+		t.Errorf("Expected the minimum version to come from the environment, got %v, %v", config, err)
+	}
+
+	t.Setenv(transport.EnvTLSMinVersion, "")
+	t.Setenv(transport.EnvTLSCiphers, "bogus")
+	if _, err := transport.NewTLSConfig(nil); err == nil {
+		t.Error("Expected an invalid cipher list to be an error")
+	}
+}
+
+func TestInsecureSkipVerifyIsOptIn(t *testing.T) {
+	t.Setenv(transport.EnvInsecureSkipVerify, "")
+	t.Setenv(transport.EnvInsecureSkipVerifyUnprefixed, "")
+	config, err := transport.NewTLSConfig(nil)
+	if err != nil || config.InsecureSkipVerify {
+		t.Errorf("Expected certificates to be verified by default, got %v, %v", config, err)
+	}
+
+	t.Setenv(transport.EnvInsecureSkipVerify, "true")
+	config, err = transport.NewTLSConfig(nil)
+	if err != nil || !config.InsecureSkipVerify {
+		t.Errorf("Expected verification to be skipped when enabled, got %v, %v", config, err)
+	}
+}
+
+func TestInsecureSkipVerifyWarnsOnStderr(t *testing.T) {
+	t.Setenv(transport.EnvInsecureSkipVerify, "")
+	t.Setenv(transport.EnvInsecureSkipVerifyUnprefixed, "true")
+
+	// the warning must be written even with logging turned off, as it is when running as an MCP server
+	level := logger.GetLevel()
+	logger.SetLevel(logger.FATAL)
+	defer logger.SetLevel(level)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	config, err := transport.NewTLSConfig(nil)
+	os.Stderr = stderr
+	w.Close()
+	output, _ := io.ReadAll(r)
+
+	if err != nil || !config.InsecureSkipVerify {
+		t.Errorf("Expected %s to skip verification, got %v, %v", transport.EnvInsecureSkipVerifyUnprefixed, config, err)
+	}
+	if !strings.Contains(string(output), "verification is DISABLED as "+transport.EnvInsecureSkipVerifyUnprefixed) {
+		t.Errorf("Expected a warning on stderr, got %q", output)
+	}
+}
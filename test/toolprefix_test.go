@@ -0,0 +1,51 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/server"
+)
+
+func echoTool(params any) (any, error) {
+	return params, nil
+}
+
+func TestToolPrefixFromEnvironment(t *testing.T) {
+	t.Setenv(server.EnvToolPrefix, "custom__")
+	if prefix := server.ToolPrefix(); prefix != "custom__" {
+		t.Errorf("Expected prefix custom__, got %q", prefix)
+	}
+	t.Setenv(server.EnvToolPrefix, "")
+	if prefix := server.ToolPrefix(); prefix != "" {
+		t.Errorf("Expected no prefix when set empty, got %q", prefix)
+	}
+}
+
+func TestRegisterPrefixedTool(t *testing.T) {
+	s := server.NewServer(nil)
+	s.ToolPrefix = "custom__"
+	s.RegisterPrefixedTool(protocol.Tool{Name: "echo"}, echoTool)
+
+	if tools := s.GetTools(); len(tools) != 1 || tools[0].Name != "custom__echo" {
+		t.Fatalf("Expected the tool to be registered as custom__echo, got %v", tools)
+	}
+	for _, name := range []string{"custom__echo", "echo"} {
+		if s.ToolHandler(name) == nil {
+			t.Errorf("Expected a handler for %s", name)
+		}
+	}
+	if s.ToolHandler("mcp___echo") != nil {
+		t.Error("Expected no handler under the default prefix")
+	}
+}
+
+func TestRegisterToolWithoutPrefix(t *testing.T) {
+	s := server.NewServer(nil)
+	s.ToolPrefix = ""
+	s.RegisterPrefixedTool(protocol.Tool{Name: "echo"}, echoTool)
+
+	if s.GetTools()[0].Name != "echo" || s.ToolHandler("echo") == nil {
+		t.Errorf("Expected the tool to be registered as echo, got %v", s.GetTools())
+	}
+}
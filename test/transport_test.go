@@ -0,0 +1,170 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/server"
+	"github.com/richard-senior/mcp/pkg/transport"
+)
+
+func TestStdioTransportWritesFramedResponses(t *testing.T) {
+	var out bytes.Buffer
+	tr := transport.NewStdioTransportWithIO(strings.NewReader(""), &out)
+
+	for i, result := range []any{map[string]any{"text": "line one\nline two"}, "ok"} {
+		resp, err := protocol.NewJsonRpcResponse(result, i)
+		if err != nil {
+			t.Fatalf("NewJsonRpcResponse failed: %v", err)
+		}
+		if err := tr.WriteResponse(resp); err != nil {
+			t.Fatalf("WriteResponse failed: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 framed messages, got %d: %q", len(lines), out.String())
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("frame is not valid JSON: %q", line)
+		}
+	}
+}
+
+// TestStdioTransportReportsMarshalError tests a response which can't be marshalled is reported and not written
+func TestStdioTransportReportsMarshalError(t *testing.T) {
+	var out bytes.Buffer
+	tr := transport.NewStdioTransportWithIO(strings.NewReader(""), &out)
+
+	resp := &protocol.JsonRpcResponse{JsonRPC: protocol.JsonRpcVersion, Result: json.RawMessage("{not json"), ID: 1}
+	if err := tr.WriteResponse(resp); err == nil {
+		t.Error("expected the marshal error for a malformed result")
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected nothing written, got %q", out.String())
+	}
+}
+
+func TestStdioTransportWritesNotifications(t *testing.T) {
+	var out bytes.Buffer
+	tr := transport.NewStdioTransportWithIO(strings.NewReader(""), &out)
+
+	notification, err := protocol.NewJsonRpcNotification(string(protocol.MethodPromptsListChanged), nil)
+	if err != nil {
+		t.Fatalf("NewJsonRpcNotification failed: %v", err)
+	}
+	if err := tr.WriteNotification(notification); err != nil {
+		t.Fatalf("WriteNotification failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Notification is not valid JSON: %q", out.String())
+	}
+	if got["method"] != "notifications/prompts/list_changed" {
+		t.Errorf("Unexpected method %v", got["method"])
+	}
+	if _, ok := got["id"]; ok {
+		t.Errorf("Expected a notification without an id, got %q", out.String())
+	}
+}
+
+func TestStdioTransportRejectsOversizedMessage(t *testing.T) {
+	big := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"text":"` + strings.Repeat("x", 200) + `"}}`
+	small := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+	tr := transport.NewStdioTransportWithIO(strings.NewReader(big+"\n"+small+"\n"), &bytes.Buffer{})
+	tr.SetMaxMessageSize(100)
+
+	if _, err := tr.ReadRequest(); !errors.Is(err, transport.ErrMessageTooLarge) {
+		t.Fatalf("Expected ErrMessageTooLarge, got %v", err)
+	}
+	// the rest of the oversized message is skipped so the next one reads cleanly
+	req, err := tr.ReadRequest()
+	if err != nil {
+		t.Fatalf("Expected the next request to be read, got %v", err)
+	}
+	if req.Method != "tools/list" {
+		t.Errorf("Expected tools/list, got %s", req.Method)
+	}
+	if _, err := tr.ReadRequest(); err != io.EOF {
+		t.Errorf("Expected EOF, got %v", err)
+	}
+}
+
+func TestServerContinuesAfterOversizedMessage(t *testing.T) {
+	t.Setenv(transport.EnvMaxMessageSize, "100")
+	big := `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"text":"` + strings.Repeat("x", 200) + `"}}`
+	small := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+	var out bytes.Buffer
+	s := server.InitInstance(transport.NewStdioTransportWithIO(strings.NewReader(big+small), &out))
+
+	if err := s.ProcessRequests(); err != io.EOF {
+		t.Fatalf("Expected the server to stop at EOF, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 responses, got %d: %q", len(lines), out.String())
+	}
+	var rejected, listed protocol.JsonRpcResponse
+	if err := json.Unmarshal([]byte(lines[0]), &rejected); err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Error == nil || rejected.Error.Code != protocol.ErrInvalidRequest || !strings.Contains(rejected.Error.Message, "too large") {
+		t.Errorf("Expected an invalid request error for the oversized message, got %s", lines[0])
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if listed.Error != nil || listed.ID != float64(2) {
+		t.Errorf("Expected the following request to succeed, got %s", lines[1])
+	}
+}
+
+func TestInitializeNegotiatesProtocolVersion(t *testing.T) {
+	requests := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"2099-01-01"}}` + "\n" +
+		`{"jsonrpc":"2.0","id":3,"method":"initialize","params":{"protocolVersion":"2020-01-01"}}` + "\n"
+	var out bytes.Buffer
+	s := server.NewServer(transport.NewStdioTransportWithIO(strings.NewReader(requests), &out))
+	s.RegisterDefaultTools()
+	if err := s.ProcessRequests(); err != io.EOF {
+		t.Fatalf("Expected the server to stop at EOF, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 responses, got %d: %q", len(lines), out.String())
+	}
+	for i, expected := range []string{"2025-03-26", "2025-06-18"} {
+		var resp struct {
+			Result struct {
+				ProtocolVersion string          `json:"protocolVersion"`
+				Capabilities    json.RawMessage `json:"capabilities"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(lines[i]), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Result.ProtocolVersion != expected {
+			t.Errorf("Expected protocol version %s, got %s", expected, lines[i])
+		}
+		// no prompts are registered, so only tools are offered
+		if string(resp.Result.Capabilities) != `{"tools":{"listChanged":true}}` {
+			t.Errorf("Expected only the tools capability, got %s", resp.Result.Capabilities)
+		}
+	}
+	var rejected protocol.JsonRpcResponse
+	if err := json.Unmarshal([]byte(lines[2]), &rejected); err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Error == nil || rejected.Error.Code != protocol.ErrInvalidParams || !strings.Contains(rejected.Error.Message, "unsupported protocol version") {
+		t.Errorf("Expected an unsupported protocol version error, got %s", lines[2])
+	}
+}
@@ -0,0 +1,231 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/richard-senior/mcp/pkg/protocol"
+	"github.com/richard-senior/mcp/pkg/tools"
+	"github.com/richard-senior/mcp/pkg/transport"
+)
+
+// TestWikipediaSearchVariationsTitleCase tests title casing of names with apostrophes, hyphens and accents
+func TestWikipediaSearchVariationsTitleCase(t *testing.T) {
+	tests := []struct {
+		query     string
+		titleCase string
+	}{
+		{"o'brien", "O'Brien"},
+		{"SINÉAD O'CONNOR", "Sinéad O'Connor"},
+		{"sinéad o’connor", "Sinéad O’Connor"},
+		// words which aren't names are tried as the title caser writes them too
+		{"don't stop me now", "Don't Stop Me Now"},
+		{"jean-paul sartre", "Jean-Paul Sartre"},
+		{"émile zola", "Émile Zola"},
+		{"ångström", "Ångström"},
+	}
+
+	for _, tt := range tests {
+		variations := tools.WikipediaSearchVariations(tt.query)
+
+		found := false
+		seen := make(map[string]bool)
+		for _, v := range variations {
+			if seen[v] {
+				t.Errorf("Duplicate variation '%s' for query '%s'", v, tt.query)
+			}
+			seen[v] = true
+			if v == tt.titleCase {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected title case variation '%s' for query '%s', got %v", tt.titleCase, tt.query, variations)
+		}
+		if variations[0] != tt.query {
+			t.Errorf("Expected the original query '%s' to be tried first, got '%s'", tt.query, variations[0])
+		}
+	}
+}
+
+// TestWikipediaSearchVariationsTransformations tests the underscore, hyphen and lowercase variations are kept
+func TestWikipediaSearchVariationsTransformations(t *testing.T) {
+	variations := tools.WikipediaSearchVariations("Elvis Presley")
+	expected := []string{
+		"Elvis Presley",
+		"elvis presley",
+		"Elvis_Presley",
+		"Elvis-Presley",
+		"elvis_presley",
+		"elvis-presley",
+	}
+	if len(variations) != len(expected) {
+		t.Fatalf("Expected %d variations, got %d: %v", len(expected), len(variations), variations)
+	}
+	for i, v := range expected {
+		if variations[i] != v {
+			t.Errorf("Expected variation %d to be '%s', got '%s'", i, v, variations[i])
+		}
+	}
+}
+
+// flakyWikipedia fails the first few Wikipedia API requests as if the connection was refused
+type flakyWikipedia struct {
+	failures int
+	requests int
+}
+
+func (f *flakyWikipedia) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.requests++
+	if f.requests <= f.failures {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+	body := `{"query":{"pages":{"1":{"title":"` + req.URL.Query().Get("titles") + `","thumbnail":{"source":"https://upload.wikimedia.org/elvis.jpg"}}}}}`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// TestWikipediaImageURLSearchRetriesTransientErrors tests a variation is retried rather than abandoned when the connection fails
+func TestWikipediaImageURLSearchRetriesTransientErrors(t *testing.T) {
+	fake := &flakyWikipedia{failures: 2}
+	transport.SetCustomHTTPClient(&http.Client{Transport: fake})
+	defer transport.SetCustomHTTPClient(nil)
+	policy := tools.WikipediaRetryPolicy
+	tools.WikipediaRetryPolicy = transport.RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+	defer func() { tools.WikipediaRetryPolicy = policy }()
+
+	imageURL, contentType, err := tools.WikipediaImageURLSearch("Elvis Presley", 100)
+	if err != nil {
+		t.Fatalf("Expected the search to succeed after retrying, got %v", err)
+	}
+	if imageURL != "https://upload.wikimedia.org/elvis.jpg" || contentType != "image/jpeg" {
+		t.Errorf("Unexpected image %s %s", imageURL, contentType)
+	}
+	if fake.requests != 3 {
+		t.Errorf("Expected the first variation to be tried 3 times, got %d requests", fake.requests)
+	}
+}
+
+// TestIsTransient tests which request errors are worth retrying
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		err       error
+		transient bool
+	}{
+		{nil, false},
+		{errors.New("no image found for query: elvis"), false},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{fmt.Errorf("failed to connect: %w", syscall.ECONNRESET), true},
+		{protocol.NewDataError(errors.New("status 503"), map[string]any{"status": 503}), true},
+		{protocol.NewDataError(errors.New("status 429"), map[string]any{"status": 429}), true},
+		{protocol.NewDataError(errors.New("status 404"), map[string]any{"status": 404}), false},
+	}
+	for _, c := range cases {
+		if transport.IsTransient(c.err) != c.transient {
+			t.Errorf("IsTransient(%v) = %v, expected %v", c.err, !c.transient, c.transient)
+		}
+	}
+}
+
+// pngHeader is enough of a PNG for the content type to be sniffed from it
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// TestWikipediaImageResultBytes tests the bytes mode embeds the image as a binary resource
+func TestWikipediaImageResultBytes(t *testing.T) {
+	result, err := tools.WikipediaImageResult("Elvis Presley", pngHeader, "", "bytes")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	toolResult, ok := result.(*protocol.ToolResult)
+	if !ok {
+		t.Fatalf("Expected a *protocol.ToolResult, got %T", result)
+	}
+	if len(toolResult.Content) != 1 || toolResult.Content[0].Type != "resource" || toolResult.Content[0].Resource == nil {
+		t.Fatalf("Expected a single resource content item, got %+v", toolResult.Content)
+	}
+	resource := toolResult.Content[0].Resource
+	if resource.MimeType != "image/png" {
+		t.Errorf("Expected the sniffed mime type image/png, got %s", resource.MimeType)
+	}
+	if resource.URI != "wikipedia-image:Elvis%20Presley" {
+		t.Errorf("Unexpected resource uri %s", resource.URI)
+	}
+	blob, err := base64.StdEncoding.DecodeString(resource.Blob)
+	if err != nil || !bytes.Equal(blob, pngHeader) {
+		t.Errorf("Expected the blob to decode to the image bytes, got %q (%v)", blob, err)
+	}
+
+	// the result must serialise as MCP resource content, not as a map with base64 data
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal result: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"type":"resource"`) || !strings.Contains(string(encoded), `"blob":`) {
+		t.Errorf("Unexpected encoding %s", encoded)
+	}
+	if strings.Contains(string(encoded), "structuredContent") || strings.Contains(string(encoded), `"text"`) {
+		t.Errorf("Expected no structured content or text in %s", encoded)
+	}
+}
+
+// TestWikipediaImageResultEncodedModes tests the base64 and datauri modes
+func TestWikipediaImageResultEncodedModes(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(pngHeader)
+	tests := []struct {
+		mode string
+		data string
+	}{
+		{"base64", encoded},
+		{"datauri", "data:image/png;base64," + encoded},
+	}
+
+	for _, tt := range tests {
+		result, err := tools.WikipediaImageResult("Elvis Presley", pngHeader, "image/png; charset=binary", tt.mode)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", tt.mode, err)
+		}
+		ret, ok := result.(map[string]any)
+		if !ok {
+			t.Fatalf("Expected a map for %s, got %T", tt.mode, result)
+		}
+		if ret["data"] != tt.data {
+			t.Errorf("Expected data %q for %s, got %q", tt.data, tt.mode, ret["data"])
+		}
+		if ret["contentType"] != "image/png" {
+			t.Errorf("Expected normalised content type for %s, got %v", tt.mode, ret["contentType"])
+		}
+		if ret["size"] != len(pngHeader) {
+			t.Errorf("Expected size %d for %s, got %v", len(pngHeader), tt.mode, ret["size"])
+		}
+	}
+
+	if _, err := tools.WikipediaImageResult("Elvis Presley", pngHeader, "image/png", "url"); err == nil {
+		t.Error("Expected an error for a mode which doesn't return image data")
+	}
+}
+
+// TestWikipediaImageInvalidOutputMode tests an unknown output mode is rejected before anything is fetched
+func TestWikipediaImageInvalidOutputMode(t *testing.T) {
+	_, err := tools.HandleWikipediaImageTool(map[string]any{"query": "Elvis Presley", "output_mode": "jpeg"})
+	if err == nil {
+		t.Fatal("Expected an error for an invalid output_mode")
+	}
+	data, ok := protocol.ErrorData(err).(map[string]any)
+	if !ok || data["parameter"] != "output_mode" {
+		t.Errorf("Expected error data naming the output_mode parameter, got %v", protocol.ErrorData(err))
+	}
+}